@@ -0,0 +1,246 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/nlopes/slack"
+)
+
+const (
+	backfillWindowDays = 7
+	// backfillDigestSize is maximum number of questions listed in digest
+	backfillDigestSize = 20
+)
+
+// StackExchangeBackfill returns command storing historical questions
+func StackExchangeBackfill(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("backfill")
+	scmd.SetShortDesc("Store historical questions of tracked tags, backfilled questions are not posted to Slack.")
+
+	fromFlag := flags.NewStringFlag("from")
+	fromFlag.SetUsage("start date e.g. 2017-01-01")
+	scmd.AddFlag(fromFlag)
+
+	toFlag := flags.NewStringFlag("to")
+	toFlag.SetUsage("end date e.g. 2017-06-01 (default: now)")
+	scmd.AddFlag(toFlag)
+
+	windowFlag := flags.NewStringFlag("window-days")
+	windowFlag.SetUsage("days of questions fetched per date window (default: 7)")
+	scmd.AddFlag(windowFlag)
+
+	taggedFlag := flags.NewStringFlag("tagged")
+	taggedFlag.SetUsage("semicolon delimited tags (default: search-advanced.tagged from config)")
+	scmd.AddFlag(taggedFlag)
+
+	digestFlag := flags.NewBoolFlag("digest")
+	digestFlag.SetUsage("Post one digest message of backfilled questions to Slack")
+	scmd.AddFlag(digestFlag)
+
+	restartFlag := flags.NewBoolFlag("restart")
+	restartFlag.SetUsage("Ignore stored checkpoint and start from --from again")
+	scmd.AddFlag(restartFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		fromArg, _ := w.Flag("from")
+		if !fromArg.Present() {
+			w.Fail("--from must be provided")
+			return
+		}
+		from, err := internal.ParseDate(fromArg.Value().String())
+		if err != nil {
+			w.Fail("--from: " + err.Error())
+			return
+		}
+		to := time.Now().UTC()
+		if toArg, _ := w.Flag("to"); toArg.Present() {
+			if to, err = internal.ParseDate(toArg.Value().String()); err != nil {
+				w.Fail("--to: " + err.Error())
+				return
+			}
+		}
+		if !to.After(from) {
+			w.Fail("--to must be after --from")
+			return
+		}
+		days := backfillWindowDays
+		if f, _ := w.Flag("window-days"); f.Present() {
+			if days, err = strconv.Atoi(f.Value().String()); err != nil || days <= 0 {
+				w.Fail("--window-days must be positive number")
+				return
+			}
+		}
+		tagged := so.Config.StackExchange.SearchAdvanced["tagged"]
+		if f, _ := w.Flag("tagged"); f.Present() {
+			tagged = f.Value().String()
+		}
+		restart, _ := w.Flag("restart")
+		questions := backfill(w, so, tagged, from, to, time.Duration(days)*24*time.Hour, restart.Present())
+		if digest, _ := w.Flag("digest"); digest.Present() {
+			slackPostBackfillDigest(w, so, tagged, from, to, questions)
+		}
+	})
+	scmd.AfterAlways(func(w *cli.Worker) {
+		if err := so.DB.Close(); err != nil {
+			w.Log.Error(err)
+		}
+		w.Log.Infof(
+			"Stack Exchange Quota usage (%d/%d)",
+			so.StackExchange.GetQuotaRemaining(),
+			so.StackExchange.GetQuotaMax(),
+		)
+	})
+	return scmd
+}
+
+// backfill walks date windows from checkpoint to given date and stores
+// questions, returns questions which were not stored before
+func backfill(w *cli.Worker, so *internal.SlackOverflow, tagged string, from time.Time, to time.Time, window time.Duration, restart bool) []internal.QuestionObj {
+	key := fmt.Sprintf("%s|%s|%d", so.Config.StackExchange.Site, tagged, from.Unix())
+	b := so.DB.FindStackExchangeBackfill(key)
+	if b.Key == "" || restart {
+		b = internal.StackExchangeBackfill{
+			Key:        key,
+			Site:       so.Config.StackExchange.Site,
+			Tagged:     tagged,
+			FromDate:   from,
+			Checkpoint: from,
+		}
+	} else {
+		w.Log.Infof("Stack Exchange: Resuming backfill from %s, %d questions stored so far.",
+			b.Checkpoint.Format("2006-01-02 15:04"), b.Questions)
+	}
+	b.ToDate = to
+
+	var stored []internal.QuestionObj
+	for b.Checkpoint.Before(to) {
+		end := b.Checkpoint.Add(window)
+		if end.After(to) {
+			end = to
+		}
+		items, complete := backfillWindow(w, so, tagged, b.Checkpoint, end)
+		for _, q := range items {
+			existed := so.DB.FindStackExchangeQuestion(q.QID).QID > 0
+			so.SyncQuestion(w, q)
+			if existed {
+				continue
+			}
+			if err := so.DB.StackExchangeQuestionBackfilled(q.QID); err != nil {
+				w.Log.Error(err)
+			}
+			stored = append(stored, q)
+			b.Questions++
+		}
+		if !complete {
+			w.Log.Warningf("Stack Exchange: Backfill stopped at %s, run same command again to resume.",
+				b.Checkpoint.Format("2006-01-02 15:04"))
+			break
+		}
+		b.Checkpoint = end
+		b.Updated = time.Now().UTC()
+		if err := so.DB.StackExchangeBackfillSave(b); err != nil {
+			w.Log.Error(err)
+			break
+		}
+		w.Log.Infof("Stack Exchange: Backfilled until %s, %d questions in window. %s",
+			end.Format("2006-01-02 15:04"), len(items),
+			quotaGauge(so.StackExchange.GetQuotaRemaining(), so.StackExchange.GetQuotaMax()))
+	}
+	if !b.Checkpoint.Before(to) {
+		w.Log.Okf("Stack Exchange: Backfill of %s from %s to %s complete, %d questions stored.",
+			tagged, from.Format("2006-01-02"), to.Format("2006-01-02"), b.Questions)
+	}
+	return stored
+}
+
+// backfillWindow fetches all questions created in date window, returns false
+// if paging stopped before all questions were received
+func backfillWindow(w *cli.Worker, so *internal.SlackOverflow, tagged string, from time.Time, to time.Time) ([]internal.QuestionObj, bool) {
+	so.QuestionFilter(w)
+	searchAdvanced, err := so.SearchAdvanced()
+	if err != nil {
+		w.Log.Error(err)
+		return nil, false
+	}
+	// Both dates are inclusive, avoid overlapping windows
+	params := map[string]interface{}{
+		"fromdate": from.Unix(),
+		"todate":   to.Unix() - 1,
+		"sort":     "creation",
+		"order":    "asc",
+	}
+	// Without tags all questions of the site are backfilled
+	if tagged != "" {
+		params["tagged"] = tagged
+	} else {
+		searchAdvanced.Parameters.Delete("tagged")
+	}
+	for param, value := range params {
+		if err := searchAdvanced.Parameters.Set(param, value); err != nil {
+			w.Log.Error(err)
+			return nil, false
+		}
+	}
+	var items []internal.QuestionObj
+	pager := so.Pager(searchAdvanced.Pages())
+	pager.MaxPages = 0
+	for pager.Next() {
+		items = append(items, searchAdvanced.Result.Items...)
+	}
+	logPager(w, so, pager)
+	return items, pager.Complete()
+}
+
+// slackPostBackfillDigest posts single message listing backfilled questions
+func slackPostBackfillDigest(w *cli.Worker, so *internal.SlackOverflow, tagged string, from time.Time, to time.Time, questions []internal.QuestionObj) {
+	if len(questions) == 0 {
+		w.Log.Info("Slack: No new questions were backfilled, digest not posted.")
+		return
+	}
+	sort.Slice(questions, func(i, j int) bool {
+		return questions[i].Score > questions[j].Score
+	})
+	var lines []string
+	for i, q := range questions {
+		if i == backfillDigestSize {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(questions)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("• %s score %d, %d answers",
+			internal.MrkdwnLink(q.ShareLink, html.UnescapeString(q.Title)), q.Score, q.AnswerCount))
+	}
+	params := slack.NewPostMessageParameters()
+	params.AsUser = false
+	params.UnfurlLinks = false
+	params.Attachments = []slack.Attachment{{
+		Fallback:   fmt.Sprintf("%d backfilled questions", len(questions)),
+		Color:      msgInactive,
+		Text:       strings.Join(lines, "\n"),
+		MarkdownIn: []string{"text"},
+	}}
+	text := fmt.Sprintf("Backfilled %d questions tagged %s from %s to %s",
+		len(questions), tagged, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	api := so.SlackClient()
+	channelID, _, err := api.PostMessage(so.Config.Slack.Channel, text, params)
+	if err != nil {
+		w.Log.Errorf("Slack channel (%s): %s", so.Config.Slack.Channel, err.Error())
+		return
+	}
+	w.Log.Infof("Slack channel (%s): backfill digest posted", channelID)
+}
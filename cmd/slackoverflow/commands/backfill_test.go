@@ -0,0 +1,35 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal/fakese"
+)
+
+func TestSlackPostBackfillDigestEscapesTitles(t *testing.T) {
+	env := newTestEnv(t, fakese.Options{Seed: 1})
+	defer env.Close()
+
+	now := time.Now()
+	slackPostBackfillDigest(env.w, env.so, "aframe", now.Add(-24*time.Hour), now, []internal.QuestionObj{{
+		QID:       1,
+		Title:     "a | b &lt;c&gt; &amp; d",
+		ShareLink: "https://stackoverflow.com/q/1",
+	}})
+
+	messages := env.slack.Messages(testChannel)
+	if len(messages) != 1 {
+		t.Fatalf("%d messages posted, want 1", len(messages))
+	}
+	want := "• <https://stackoverflow.com/q/1|a / b &lt;c&gt; &amp; d> score 0, 0 answers"
+	if text := attachmentText(t, messages[0]); !strings.Contains(text, want) {
+		t.Errorf("digest %q does not contain %q", text, want)
+	}
+}
@@ -41,6 +41,9 @@ func printConfig(w *cli.Worker, so *internal.SlackOverflow) {
 
 	slack.AddRow("Channel", so.Config.Slack.Channel)
 	slack.AddRow("Channel name", so.Config.Slack.ChannelName)
+	slack.AddRow("Socket Mode", so.Config.Slack.SocketMode)
+	slack.AddRow("App token", so.Config.Slack.AppToken)
+	slack.AddRow("Inbox channel", so.Config.Slack.InboxChannel)
 	slack.Print()
 
 	stackexchange := internal.NewTable("StackExchange Configuration", " ")
@@ -48,9 +51,20 @@ func printConfig(w *cli.Worker, so *internal.SlackOverflow) {
 	stackexchange.AddRow("API Version", so.Config.StackExchange.APIVersion)
 
 	stackexchange.AddRow("Key", so.Config.StackExchange.Key)
+	stackexchange.AddRow("Access token", so.Config.StackExchange.AccessToken)
 
 	stackexchange.AddRow("Site", so.Config.StackExchange.Site)
 	stackexchange.AddRow("Tagged", so.Config.StackExchange.SearchAdvanced["tagged"])
 	stackexchange.AddRow("Questions to watch", so.Config.StackExchange.QuestionsToWatch)
+	stackexchange.AddRow("Question body", so.Config.StackExchange.QuestionBody)
+	if so.Config.StackExchange.QuestionBody {
+		stackexchange.AddRow("Body excerpt length", so.Config.StackExchange.ExcerptLength())
+	}
+	stackexchange.AddRow("Users refresh interval", so.Config.StackExchange.UsersRefreshInterval())
+	stackexchange.AddRow("Max lookback of new questions", so.Config.StackExchange.MaxLookbackDuration())
+	stackexchange.AddRow("Real-time", so.Config.StackExchange.Realtime)
+	for name, filter := range so.Config.StackExchange.Filters {
+		stackexchange.AddRow("Filter "+name, filter)
+	}
 	stackexchange.Print()
 }
@@ -0,0 +1,186 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal/fakese"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal/fakeslack"
+)
+
+// Dev returns development commands
+func Dev(so *internal.SlackOverflow) cli.Command {
+	cmd := cli.NewCommand("dev")
+	cmd.SetShortDesc("Development helpers see slackoverflow dev --help for more info.")
+	cmd.AddSubcommand(DevFakeStackExchange(so))
+	cmd.AddSubcommand(DevFakeSlack(so))
+	return cmd
+}
+
+// DevFakeStackExchange returns command serving fake Stack Exchange API
+func DevFakeStackExchange(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("fake-stackexchange")
+	scmd.SetShortDesc("Serve fake Stack Exchange API with generated questions, set stackexchange.api-host to its address.")
+
+	addrFlag := flags.NewStringFlag("addr")
+	addrFlag.SetUsage("address to listen on (default: 127.0.0.1:8090)")
+	scmd.AddFlag(addrFlag)
+
+	seedFlag := flags.NewStringFlag("seed")
+	seedFlag.SetUsage("seed of generated dataset (default: 1)")
+	scmd.AddFlag(seedFlag)
+
+	questionsFlag := flags.NewStringFlag("questions")
+	questionsFlag.SetUsage("number of questions created during last day (default: 50)")
+	scmd.AddFlag(questionsFlag)
+
+	tagsFlag := flags.NewStringFlag("tags")
+	tagsFlag.SetUsage("semicolon delimited tags of questions (default: tracked tags or aframe)")
+	scmd.AddFlag(tagsFlag)
+
+	newFlag := flags.NewStringFlag("new-every")
+	newFlag.SetUsage("create new question at interval e.g. 30s (default: never)")
+	scmd.AddFlag(newFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		opts := fakese.Options{Seed: 1}
+		// Configuration is optional, it only provides defaults
+		if err := so.Load(w); err == nil && so.Config.IsLoaded() {
+			opts.Site = so.Config.StackExchange.Site
+			opts.Tags = so.TrackedTags()
+		}
+		if f, _ := w.Flag("seed"); f.Present() {
+			seed, err := strconv.ParseInt(f.Value().String(), 10, 64)
+			if err != nil {
+				w.Fail("--seed must be a number")
+				return
+			}
+			opts.Seed = seed
+		}
+		if f, _ := w.Flag("questions"); f.Present() {
+			questions, err := strconv.Atoi(f.Value().String())
+			if err != nil || questions <= 0 {
+				w.Fail("--questions must be positive number")
+				return
+			}
+			opts.Questions = questions
+		}
+		if f, _ := w.Flag("tags"); f.Present() {
+			opts.Tags = strings.Split(f.Value().String(), ";")
+		}
+		var newEvery time.Duration
+		if f, _ := w.Flag("new-every"); f.Present() {
+			var err error
+			if newEvery, err = time.ParseDuration(f.Value().String()); err != nil || newEvery <= 0 {
+				w.Fail("--new-every must be duration e.g. 30s")
+				return
+			}
+		}
+		addr := "127.0.0.1:8090"
+		if f, _ := w.Flag("addr"); f.Present() {
+			addr = f.Value().String()
+		}
+
+		fake := fakese.New(opts)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		go http.Serve(listener, fake)
+		w.Log.Okf("Fake Stack Exchange API listening on http://%s", listener.Addr().String())
+		w.Log.Infof("Serving %s", fake.String())
+		w.Log.Info("Set stackexchange.api-host in slackoverflow.yaml to the address above.")
+
+		var ticks <-chan time.Time
+		if newEvery > 0 {
+			ticker := time.NewTicker(newEvery)
+			defer ticker.Stop()
+			ticks = ticker.C
+		}
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		for {
+			select {
+			case <-ticks:
+				q := fake.AddQuestion()
+				w.Log.Infof("New question %d: %s", q.QID, q.Title)
+			case <-sig:
+				listener.Close()
+				w.Log.Infof("Served %d requests, quota remaining %d.", len(fake.Requests()), fake.QuotaRemaining())
+				return
+			}
+		}
+	})
+	return scmd
+}
+
+// DevFakeSlack returns command serving fake Slack Web API
+func DevFakeSlack(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("fake-slack")
+	scmd.SetShortDesc("Serve fake Slack Web API logging all calls, set slack.api-host to its address.")
+
+	addrFlag := flags.NewStringFlag("addr")
+	addrFlag.SetUsage("address to listen on (default: 127.0.0.1:8091)")
+	scmd.AddFlag(addrFlag)
+
+	channelFlag := flags.NewStringFlag("channel")
+	channelFlag.SetUsage("additional channel name e.g. aframe-questions")
+	scmd.AddFlag(channelFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		token := ""
+		if err := so.Load(w); err == nil && so.Config.IsLoaded() {
+			token = so.Config.Slack.Token
+		}
+		fake := fakeslack.New(token)
+		if f, _ := w.Flag("channel"); f.Present() {
+			w.Log.Infof("Channel #%s has id %s", f.Value().String(), fake.AddChannel(f.Value().String()))
+		}
+		addr := "127.0.0.1:8091"
+		if f, _ := w.Flag("addr"); f.Present() {
+			addr = f.Value().String()
+		}
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		// Log calls as they arrive
+		var mu sync.Mutex
+		logged := 0
+		handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			fake.ServeHTTP(rw, r)
+			mu.Lock()
+			defer mu.Unlock()
+			calls := fake.Calls()
+			for _, call := range calls[logged:] {
+				w.Log.Infof("%s %s", call.Method, call.Params.Get("channel"))
+			}
+			logged = len(calls)
+		})
+		go http.Serve(listener, handler)
+		w.Log.Okf("Fake Slack API listening on http://%s/api", listener.Addr().String())
+		w.Log.Info("Set slack.api-host in slackoverflow.yaml to the address above.")
+
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		<-sig
+		listener.Close()
+		w.Log.Infof("Served %d calls.", len(fake.Calls()))
+	})
+	return scmd
+}
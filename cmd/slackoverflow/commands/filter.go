@@ -0,0 +1,154 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+)
+
+// StackExchangeFilter returns Stack Exchange filter management command
+func StackExchangeFilter(so *internal.SlackOverflow) cli.Command {
+	cmd := cli.NewCommand("filter")
+	cmd.SetShortDesc("Create and inspect Stack Exchange API filters see slackoverflow stackexchange filter --help for more info.")
+	cmd.AddSubcommand(StackExchangeFilterCreate(so))
+	cmd.AddSubcommand(StackExchangeFilterShow(so))
+	return cmd
+}
+
+// StackExchangeFilterCreate returns command creating named filter
+func StackExchangeFilterCreate(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("create")
+	scmd.SetShortDesc("Create filter with /filters/create and store it in config with given name.")
+
+	nameFlag := flags.NewStringFlag("name")
+	nameFlag.SetUsage("name of the filter, can be used as filter value in search-advanced and questions config")
+	scmd.AddFlag(nameFlag)
+
+	baseFlag := flags.NewStringFlag("base")
+	baseFlag.SetUsage("base filter: default, withbody, none or total (default: default)")
+	scmd.AddFlag(baseFlag)
+
+	includeFlag := flags.NewStringFlag("include")
+	includeFlag.SetUsage("comma separated fields to include e.g. question.body,answer.owner")
+	scmd.AddFlag(includeFlag)
+
+	excludeFlag := flags.NewStringFlag("exclude")
+	excludeFlag.SetUsage("comma separated fields to exclude e.g. question.view_count")
+	scmd.AddFlag(excludeFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		name, _ := w.Flag("name")
+		if !name.Present() || name.Value().String() == "" {
+			w.Fail("--name must be provided")
+			return
+		}
+		base := "default"
+		if b, _ := w.Flag("base"); b.Present() {
+			base = b.Value().String()
+		}
+		include, _ := w.Flag("include")
+		exclude, _ := w.Flag("exclude")
+		filter, err := so.StackExchange.FiltersCreate(base,
+			splitFilterFields(include.Value().String()),
+			splitFilterFields(exclude.Value().String()))
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		so.Config.StackExchange.SetFilter(name.Value().String(), filter.Filter)
+		if err := so.SaveConfig(); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		w.Log.Okf("Filter %s created: %s", name.Value().String(), filter.Filter)
+		printFilter(name.Value().String(), filter)
+	})
+	scmd.AfterAlways(func(w *cli.Worker) {
+		dryRunDone(w, so)
+	})
+	return scmd
+}
+
+// StackExchangeFilterShow returns command decoding filters
+func StackExchangeFilterShow(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("show")
+	scmd.SetShortDesc("Show fields included by filter, all filters stored in config if --filter is not set.")
+
+	filterFlag := flags.NewStringFlag("filter")
+	filterFlag.SetUsage("filter name stored in config or filter itself e.g. !6hYwbNNZ(*eH3a3)XT0aZCOGTo-kwAtAoVF5vC378NPI6Y")
+	scmd.AddFlag(filterFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		// Filter name by filter
+		names := make(map[string]string)
+		if f, _ := w.Flag("filter"); f.Present() {
+			value := f.Value().String()
+			names[so.Config.StackExchange.ResolveFilter(value)] = value
+		} else {
+			for name, filter := range so.Config.StackExchange.Filters {
+				names[filter] = name
+			}
+		}
+		if len(names) == 0 {
+			w.Log.Line("There are no filters stored in config.")
+			return
+		}
+		var filters []string
+		for filter := range names {
+			filters = append(filters, filter)
+		}
+		sort.Strings(filters)
+		decoded, err := so.StackExchange.FiltersRead(filters...)
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		for _, filter := range decoded {
+			printFilter(names[filter.Filter], filter)
+		}
+	})
+	return scmd
+}
+
+func printFilter(name string, filter internal.FilterObj) {
+	table := internal.NewTable("Filter "+name, filter.Filter)
+	table.AddRow("Type", filter.FilterType)
+	fields := append([]string{}, filter.IncludedFields...)
+	sort.Strings(fields)
+	for i, field := range fields {
+		label := ""
+		if i == 0 {
+			label = "Fields"
+		}
+		table.AddRow(label, field)
+	}
+	table.Print()
+}
+
+// splitFilterFields splits comma or semicolon separated fields
+func splitFilterFields(value string) []string {
+	var fields []string
+	for _, field := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';'
+	}) {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,192 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"strings"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/nlopes/slack"
+)
+
+const inboxBodyLength = 300
+
+// inboxItemTypes describes inbox item and notification types
+var inboxItemTypes = map[string]string{
+	"new_answer":                   ":speech_balloon: New answer",
+	"comment":                      ":memo: New comment",
+	"chat_message":                 ":left_speech_bubble: Chat mention",
+	"meta_question":                ":question: Meta question",
+	"post_notice":                  ":pushpin: Post notice",
+	"moderator_message":            ":shield: Moderator message",
+	"careers_message":              ":briefcase: Careers message",
+	"careers_invitations":          ":briefcase: Careers invitation",
+	"accounts_associated":          ":link: Accounts associated",
+	"badge_earned":                 ":medal: Badge earned",
+	"profile_activity":             ":bust_in_silhouette: Profile activity",
+	"bounty_expired":               ":hourglass: Bounty expired",
+	"bounty_expires_in_one_day":    ":hourglass_flowing_sand: Bounty expires in one day",
+	"bounty_expires_in_three_days": ":hourglass_flowing_sand: Bounty expires in three days",
+	"edit_suggested":               ":pencil2: Edit suggested",
+	"new_privilege":                ":tada: New privilege",
+	"post_migrated":                ":airplane: Post migrated",
+	"reputation_bonus":             ":chart_with_upwards_trend: Reputation bonus",
+	"substantive_edit":             ":pencil2: Substantive edit",
+	"registration_reminder":        ":bell: Registration reminder",
+	"generic":                      ":bell: Notification",
+}
+
+// StackExchangeInbox returns command reading inbox of authorized account
+func StackExchangeInbox(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("inbox")
+	scmd.SetShortDesc("Fetch unread inbox items and notifications of authorized Stack Exchange account.")
+
+	relayFlag := flags.NewBoolFlag("relay")
+	relayFlag.SetUsage("Relay new items to Slack channel configured as slack.inbox-channel")
+	scmd.AddFlag(relayFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		if so.Config.StackExchange.AccessToken == "" {
+			w.Fail("Stack Exchange access token is not configured, see slackoverflow stackexchange login")
+			return
+		}
+		getInboxItems(w, so)
+		if relay, _ := w.Flag("relay"); relay.Present() {
+			slackRelayInbox(w, so)
+			return
+		}
+		items, err := so.DB.StackExchangeInboxItemsNotRelayed()
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		table := internal.NewTable("Created", "Source", "Type", "Site", "Title", "Link")
+		for _, item := range items {
+			table.AddRow(
+				item.Created.Format("2006-01-02 15:04"),
+				item.Source,
+				item.ItemType,
+				item.Site,
+				item.Title,
+				item.Link,
+			)
+		}
+		table.Print()
+	})
+	return scmd
+}
+
+// getInboxItems stores unread inbox items and notifications, items already
+// in database are ignored so that each item is relayed only once
+func getInboxItems(w *cli.Worker, so *internal.SlackOverflow) {
+	w.Log.Info("Stack Exchange: Checking inbox.")
+	var items []internal.StackExchangeInboxItem
+
+	inbox, err := so.MeInboxUnread()
+	if err != nil {
+		w.Log.Error(err)
+		return
+	}
+	pager := so.Pager(inbox.Pages())
+	for pager.Next() {
+		for _, item := range inbox.Result.Items {
+			items = append(items, item.InboxItem())
+		}
+	}
+	logPager(w, so, pager)
+	if pager.Err() != nil {
+		return
+	}
+
+	notifications, err := so.MeNotificationsUnread()
+	if err != nil {
+		w.Log.Error(err)
+		return
+	}
+	pager = so.Pager(notifications.Pages())
+	for pager.Next() {
+		for _, notification := range notifications.Result.Items {
+			items = append(items, notification.InboxItem())
+		}
+	}
+	logPager(w, so, pager)
+
+	stored := 0
+	for _, item := range items {
+		created, err := so.DB.StackExchangeInboxItemCreate(item)
+		if err != nil {
+			w.Log.Error(err)
+			continue
+		}
+		if created {
+			stored++
+		}
+	}
+	w.Log.Infof("Stack Exchange: %d unread inbox items, %d new.", len(items), stored)
+}
+
+// slackRelayInbox posts inbox items which are not relayed yet to inbox channel
+func slackRelayInbox(w *cli.Worker, so *internal.SlackOverflow) {
+	channel := so.Config.Slack.InboxChannel
+	if channel == "" {
+		w.Log.Debug("Slack: inbox channel is not configured.")
+		return
+	}
+	items, err := so.DB.StackExchangeInboxItemsNotRelayed()
+	if err != nil {
+		w.Log.Error(err)
+		return
+	}
+	api := so.SlackClient()
+	for _, item := range items {
+		params := slack.NewPostMessageParameters()
+		params.AsUser = false
+		params.UnfurlLinks = false
+		params.Attachments = []slack.Attachment{inboxItemAttachment(item)}
+		if _, _, err := api.PostMessage(channel, "", params); err != nil {
+			w.Log.Errorf("Slack channel (%s): %s", channel, err.Error())
+			return
+		}
+		if err := so.DB.StackExchangeInboxItemRelayed(item.Key); err != nil {
+			w.Log.Error(err)
+		}
+	}
+	if len(items) > 0 {
+		w.Log.Infof("Slack channel (%s): relayed %d inbox items.", channel, len(items))
+	}
+}
+
+// inboxItemAttachment renders inbox item or notification
+func inboxItemAttachment(item internal.StackExchangeInboxItem) slack.Attachment {
+	pretext, ok := inboxItemTypes[item.ItemType]
+	if !ok {
+		pretext = ":bell: " + strings.Replace(item.ItemType, "_", " ", -1)
+	}
+	title := item.Title
+	if title == "" {
+		title = item.Link
+	}
+	footer := item.Created.Format("2006-01-02 15:04 MST")
+	if item.Site != "" {
+		footer = item.Site + " • " + footer
+	}
+	text := internal.HTMLToMrkdwn(item.Body, inboxBodyLength)
+	return slack.Attachment{
+		Fallback:   pretext + ": " + title,
+		Pretext:    pretext,
+		Title:      title,
+		TitleLink:  item.Link,
+		Text:       text,
+		Color:      msgNotAnswered,
+		Footer:     footer,
+		MarkdownIn: []string{"text", "pretext"},
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+)
+
+const slashCommand = "/slackoverflow"
+
+// slashSubcommand handles "/slackoverflow <name> args..."
+type slashSubcommand struct {
+	usage string
+	do    func(w *cli.Worker, so *internal.SlackOverflow, cmd internal.SlackCommand, args []string) (*internal.SlackResponse, error)
+}
+
+// slashSubcommands available with /slackoverflow
+var slashSubcommands = map[string]slashSubcommand{}
+
+// registerSlackHandlers attaches all SlackOverflow handlers to dispatcher
+func registerSlackHandlers(w *cli.Worker, so *internal.SlackOverflow) {
+	so.SlackDispatcher.HandleCommand(slashCommand, func(cmd internal.SlackCommand) (*internal.SlackResponse, error) {
+		args := strings.Fields(cmd.Text)
+		if len(args) == 0 {
+			return slashHelp(), nil
+		}
+		sub, ok := slashSubcommands[args[0]]
+		if !ok {
+			return slashHelp(), nil
+		}
+		w.Log.Infof("Slack: %s %s by %s", slashCommand, cmd.Text, cmd.UserName)
+		return sub.do(w, so, cmd, args[1:])
+	})
+	so.SlackDispatcher.HandleInteraction(triageCallbackID, handleTriage(w, so))
+}
+
+// slashHelp lists available slash subcommands
+func slashHelp() *internal.SlackResponse {
+	var lines []string
+	for name, sub := range slashSubcommands {
+		lines = append(lines, "`"+slashCommand+" "+name+" "+sub.usage+"`")
+	}
+	sort.Strings(lines)
+	text := "Usage:\n" + strings.Join(lines, "\n")
+	if len(lines) == 0 {
+		text = "There are no commands available."
+	}
+	return &internal.SlackResponse{ResponseType: "ephemeral", Text: text}
+}
+
+// startSlackListener receives Slack payloads over Socket Mode or when httpAddr
+// is not empty by serving HTTP endpoints. Blocks until ctx is canceled or
+// listener fails.
+func startSlackListener(ctx context.Context, w *cli.Worker, so *internal.SlackOverflow, httpAddr string) error {
+	registerSlackHandlers(w, so)
+
+	if httpAddr != "" {
+		srv := &http.Server{Addr: httpAddr, Handler: so.SlackDispatcher.ServeMux()}
+		go func() {
+			<-ctx.Done()
+			srv.Close()
+		}()
+		w.Log.Okf("Slack: serving /slack/events, /slack/commands and /slack/interactive on %s", httpAddr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	}
+
+	socketMode, err := so.SlackSocketMode()
+	if err != nil {
+		return err
+	}
+	return socketMode.Run(ctx, w)
+}
@@ -0,0 +1,173 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/howi-ce/howi/std/errors"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/nlopes/slack"
+)
+
+func init() {
+	slashSubcommands["link"] = slashSubcommand{"<stack exchange user id>", slashLink}
+	slashSubcommands["unlink"] = slashSubcommand{"", slashUnlink}
+}
+
+// SlackLink returns command to link Slack users with Stack Exchange accounts
+func SlackLink(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("link")
+	scmd.SetShortDesc("Link Slack user to Stack Exchange account so that their answers get credited.")
+
+	suFlag := flags.NewStringFlag("slack-user")
+	suFlag.SetUsage("Slack user ID e.g. U024BE7LH")
+	scmd.AddFlag(suFlag)
+
+	seuFlag := flags.NewStringFlag("stackexchange-user")
+	seuFlag.SetUsage("Stack Exchange user ID of given site")
+	scmd.AddFlag(seuFlag)
+
+	rmFlag := flags.NewBoolFlag("remove")
+	rmFlag.SetUsage("Remove link of given Slack user")
+	scmd.AddFlag(rmFlag)
+
+	lsFlag := flags.NewBoolFlag("list")
+	lsFlag.SetUsage("List linked users")
+	scmd.AddFlag(lsFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		slackUser, _ := w.Flag("slack-user")
+		seUser, _ := w.Flag("stackexchange-user")
+		remove, _ := w.Flag("remove")
+		list, _ := w.Flag("list")
+
+		switch {
+		case list.Present():
+			printUserLinks(w, so)
+		case remove.Present() && slackUser.Present():
+			if err := so.DB.SlackUserLinkDelete(slackUser.Value().String()); err != nil {
+				w.Fail(err.Error())
+				return
+			}
+			w.Log.Okf("Slack user %s unlinked", slackUser.Value().String())
+		case slackUser.Present() && seUser.Present():
+			msg, err := linkUser(so, slackUser.Value().String(), seUser.Value().String())
+			if err != nil {
+				w.Fail(err.Error())
+				return
+			}
+			w.Log.Ok(msg)
+		default:
+			w.Fail("--slack-user and --stackexchange-user, --remove or --list must be provided")
+		}
+	})
+	return scmd
+}
+
+func printUserLinks(w *cli.Worker, so *internal.SlackOverflow) {
+	links, err := so.DB.SlackUserLinkGetAll()
+	if err != nil {
+		w.Fail(err.Error())
+		return
+	}
+	table := internal.NewTable("Slack user", "Stack Exchange user", "Display name", "Linked")
+	for _, link := range links {
+		user := so.DB.FindStackExchangeUser(link.UID)
+		table.AddRow(link.SlackUID, link.UID, user.DisplayName, link.Created.Format("2006-01-02"))
+	}
+	table.Print()
+}
+
+func linkUser(so *internal.SlackOverflow, slackUID string, seUID string) (string, error) {
+	UID, err := strconv.Atoi(seUID)
+	if err != nil || UID <= 0 {
+		return "", errors.Newf("invalid Stack Exchange user id %q", seUID)
+	}
+	msg, err := so.DB.SlackUserLinkSave(internal.SlackUserLink{SlackUID: slackUID, UID: UID})
+	if err != nil {
+		return msg, err
+	}
+	// Answers given before the link are not announced
+	return msg, so.DB.StackExchangeUserAnswersCredited(UID)
+}
+
+func slashLink(w *cli.Worker, so *internal.SlackOverflow, cmd internal.SlackCommand, args []string) (*internal.SlackResponse, error) {
+	if len(args) != 1 {
+		return &internal.SlackResponse{
+			ResponseType: "ephemeral",
+			Text:         "Usage: `" + slashCommand + " link <stack exchange user id>`",
+		}, nil
+	}
+	msg, err := linkUser(so, cmd.UserID, args[0])
+	if err != nil {
+		return nil, err
+	}
+	w.Log.Ok(msg)
+	return &internal.SlackResponse{
+		ResponseType: "ephemeral",
+		Text:         fmt.Sprintf(":link: Linked to Stack Exchange user %s, your answers will be credited.", args[0]),
+	}, nil
+}
+
+func slashUnlink(w *cli.Worker, so *internal.SlackOverflow, cmd internal.SlackCommand, args []string) (*internal.SlackResponse, error) {
+	if err := so.DB.SlackUserLinkDelete(cmd.UserID); err != nil {
+		return nil, err
+	}
+	return &internal.SlackResponse{ResponseType: "ephemeral", Text: "Your Stack Exchange account is unlinked."}, nil
+}
+
+// slackCreditAnswers mentions linked users in question thread when they
+// answered the question or their answer was accepted
+func slackCreditAnswers(w *cli.Worker, so *internal.SlackOverflow) {
+	answers, err := so.DB.StackExchangeAnswersToCredit()
+	if err != nil {
+		w.Log.Error(err)
+		return
+	}
+	if len(answers) == 0 {
+		return
+	}
+	w.Log.Info("Slack: Crediting answers of linked users.")
+
+	api := so.SlackClient()
+	for _, a := range answers {
+		link := so.DB.FindSlackQuestion(a.QID)
+		// Answer is credited once its question is posted to Slack
+		if link.QID == 0 {
+			continue
+		}
+		var text string
+		if !a.Credited {
+			text = fmt.Sprintf(":tada: <@%s> answered this question.", a.SlackUID)
+		}
+		if a.IsAccepted {
+			if text != "" {
+				text += "\n"
+			}
+			text += fmt.Sprintf(":white_check_mark: <@%s>'s answer was accepted.", a.SlackUID)
+		}
+		params := slack.NewPostMessageParameters()
+		params.AsUser = false
+		params.LinkNames = 1
+		params.ThreadTimestamp = link.TS
+		if _, _, err := api.PostMessage(link.Channel, text, params); err != nil {
+			w.Log.Errorf("Slack channel (%s): %s", link.Channel, err.Error())
+			continue
+		}
+		if err := so.DB.StackExchangeAnswerCredited(a.AID, a.IsAccepted); err != nil {
+			w.Log.Error(err)
+			continue
+		}
+		w.Log.Infof("Slack: credited answer %d to %s", a.AID, a.SlackUID)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal/fakese"
+)
+
+// addAnswer of user to question
+func addAnswer(t *testing.T, env *testEnv, AID int, QID int, UID int, accepted bool) {
+	a := internal.AnswerObj{
+		AID:              AID,
+		QID:              QID,
+		Owner:            internal.ShallowUserObj{UID: UID},
+		IsAccepted:       accepted,
+		CreationDate:     time.Now().Unix(),
+		LastActivityDate: time.Now().Unix(),
+	}
+	if _, err := env.so.DB.SyncStackExchangeAnswer(a); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// creditMessages returns number of messages mentioning slackUID
+func creditMessages(env *testEnv, slackUID string) int {
+	n := 0
+	for _, m := range env.slack.Messages(testChannel) {
+		if strings.Contains(m.Text, "<@"+slackUID+">") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSlackCreditAnswers(t *testing.T) {
+	env := newTestEnv(t, fakese.Options{Seed: 1})
+	defer env.Close()
+	questions := addQuestions(t, env, 2)
+	posted, unposted := questions[0], questions[1]
+	if _, err := env.so.DB.SlackQuestionCreate(internal.SlackQuestion{
+		QID: posted.QID, Channel: testChannel, TS: "1500000000.000001",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Answers given before the link are not announced
+	addAnswer(t, env, 1, posted.QID, 100, true)
+	if _, err := linkUser(env.so, "U100", "100"); err != nil {
+		t.Fatal(err)
+	}
+	slackCreditAnswers(env.w, env.so)
+	if n := creditMessages(env, "U100"); n != 0 {
+		t.Fatalf("%d credit messages of answers given before link, want 0", n)
+	}
+
+	addAnswer(t, env, 2, posted.QID, 100, false)
+	addAnswer(t, env, 3, unposted.QID, 100, false)
+	slackCreditAnswers(env.w, env.so)
+	if n := creditMessages(env, "U100"); n != 1 {
+		t.Fatalf("%d credit messages, want 1", n)
+	}
+
+	// Answer is credited when its question is posted to Slack
+	if _, err := env.so.DB.SlackQuestionCreate(internal.SlackQuestion{
+		QID: unposted.QID, Channel: testChannel, TS: "1500000000.000002",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	slackCreditAnswers(env.w, env.so)
+	if n := creditMessages(env, "U100"); n != 2 {
+		t.Fatalf("%d credit messages after question was posted, want 2", n)
+	}
+	slackCreditAnswers(env.w, env.so)
+	if n := creditMessages(env, "U100"); n != 2 {
+		t.Errorf("%d credit messages after second run, want 2", n)
+	}
+}
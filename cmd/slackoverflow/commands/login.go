@@ -0,0 +1,131 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/howi-ce/howi/std/errors"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+)
+
+const loginTimeout = 5 * time.Minute
+
+// StackExchangeLogin returns command obtaining OAuth access token
+func StackExchangeLogin(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("login")
+	scmd.SetShortDesc("Authorize SlackOverflow with Stack Exchange account, access token raises quota to 10000 requests and allows user scoped data.")
+
+	cidFlag := flags.NewStringFlag("client-id")
+	cidFlag.SetUsage("Client Id of your Stack Apps app, OAuth Domain of the app must be localhost")
+	scmd.AddFlag(cidFlag)
+
+	csFlag := flags.NewStringFlag("client-secret")
+	csFlag.SetUsage("Client Secret of your Stack Apps app, explicit flow is used if set")
+	scmd.AddFlag(csFlag)
+
+	scopeFlag := flags.NewStringFlag("scope")
+	scopeFlag.SetUsage("comma separated scopes (default: read_inbox,no_expiry)")
+	scmd.AddFlag(scopeFlag)
+
+	addrFlag := flags.NewStringFlag("listen")
+	addrFlag.SetUsage("address of local redirect listener (default: localhost:8976)")
+	scmd.AddFlag(addrFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		if f, _ := w.Flag("client-id"); f.Present() {
+			so.Config.StackExchange.ClientID = f.Value().String()
+		}
+		if f, _ := w.Flag("client-secret"); f.Present() {
+			so.Config.StackExchange.ClientSecret = f.Value().String()
+		}
+		scope := "read_inbox,no_expiry"
+		if f, _ := w.Flag("scope"); f.Present() {
+			scope = f.Value().String()
+		}
+		addr := "localhost:8976"
+		if f, _ := w.Flag("listen"); f.Present() {
+			addr = f.Value().String()
+		}
+		if so.Config.StackExchange.Key == "" {
+			w.Log.Warning("Stack Exchange key is not configured, access token can not be used without key.")
+		}
+
+		token, err := stackExchangeLogin(w, so, addr, scope)
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		so.Config.StackExchange.AccessToken = token.AccessToken
+		so.Config.StackExchange.AccessTokenExpires = 0
+		if !token.Expires.IsZero() {
+			so.Config.StackExchange.AccessTokenExpires = token.Expires.Unix()
+		}
+		if err := so.SaveConfig(); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		if token.Expires.IsZero() {
+			w.Log.Ok("Stack Exchange access token stored, token does not expire.")
+		} else {
+			w.Log.Okf("Stack Exchange access token stored, token expires %s.", token.Expires.Local().Format("2006-01-02 15:04"))
+		}
+	})
+	scmd.AfterAlways(func(w *cli.Worker) {
+		dryRunDone(w, so)
+	})
+	return scmd
+}
+
+// stackExchangeLogin runs local redirect listener until OAuth flow completes
+func stackExchangeLogin(w *cli.Worker, so *internal.SlackOverflow, addr string, scope string) (internal.StackExchangeToken, error) {
+	var token internal.StackExchangeToken
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return token, err
+	}
+	oauth := &internal.StackExchangeOAuth{
+		ClientID:     so.Config.StackExchange.ClientID,
+		ClientSecret: so.Config.StackExchange.ClientSecret,
+		Scope:        scope,
+		RedirectURL:  "http://" + addr + "/",
+		Transport:    so.HTTPTransport(),
+	}
+	authURL, err := oauth.AuthURL()
+	if err != nil {
+		ln.Close()
+		return token, err
+	}
+
+	result := make(chan error, 1)
+	srv := &http.Server{Handler: oauth.Handler(result, &token)}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	w.Log.Line("Open following URL in your browser and approve access:")
+	w.Log.Line(authURL)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+	select {
+	case err = <-result:
+		return token, err
+	case <-sig:
+		return token, errors.New("login canceled")
+	case <-time.After(loginTimeout):
+		return token, errors.Newf("login timed out after %s", loginTimeout)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+)
+
+// realtimeCollect is how long question ids are collected after first one is
+// queued so that burst of questions is fetched with single request
+const realtimeCollect = 5 * time.Second
+
+// consumeRealtime fetches questions queued by real-time source with batched
+// questions/{ids} requests and posts them to Slack until context is canceled
+func consumeRealtime(ctx context.Context, w *cli.Worker, so *internal.SlackOverflow, realtime *internal.StackExchangeRealtime) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-realtime.Queued():
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(realtimeCollect):
+		}
+		ids := realtime.TakeIDs()
+		if len(ids) == 0 {
+			continue
+		}
+		runMu.Lock()
+		getRealtimeQuestions(w, so, ids)
+		slackPostNewQuestions(w, so)
+		runMu.Unlock()
+	}
+}
+
+// getRealtimeQuestions fetches and stores questions of given ids
+func getRealtimeQuestions(w *cli.Worker, so *internal.SlackOverflow, ids []int) {
+	w.Log.Infof("Stack Exchange: Getting %d question(s) received in real-time.", len(ids))
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = strconv.Itoa(id)
+	}
+	so.QuestionFilter(w)
+	for batch := range so.QuestionBatches(strings.Join(strIDs, ";"), so.Config.StackExchange.ConcurrentRequests) {
+		if batch.Err != nil {
+			logStackExchangeError(w, so, batch.Err)
+		}
+		for _, q := range batch.Items {
+			w.Log.Infof("Question: %s", q.Title)
+			w.Log.Infof("Url:      %s", q.ShareLink)
+			so.SyncQuestion(w, q)
+		}
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/howi-ce/howi/addon/application/plugin/cli"
 	"github.com/howi-ce/howi/std/errors"
 	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
-	"github.com/nlopes/slack"
 )
 
 // Reconfigure command for SlackOverflow
@@ -68,7 +67,9 @@ func configureSlack(w *cli.Worker, so *internal.SlackOverflow) error {
 	w.Log.Notice("Configuring Slack API Client")
 
 	// Set Slack Defaults
-	so.Config.Slack.SetAPIhost("https://slack.com/api")
+	if so.Config.Slack.APIHost == "" {
+		so.Config.Slack.SetAPIhost(internal.SlackAPIHost)
+	}
 	so.Config.Slack.Enable()
 
 	reader := bufio.NewReader(os.Stdin)
@@ -80,7 +81,7 @@ func configureSlack(w *cli.Worker, so *internal.SlackOverflow) error {
 	so.Config.Slack.SetToken(strings.TrimSpace(token))
 
 	w.Log.Line("Fetching available Slack channels.")
-	api := slack.New(so.Config.Slack.Token)
+	api := so.SlackClient()
 	channels, err := api.GetChannels(true)
 	if err != nil {
 		return err
@@ -115,6 +116,18 @@ func configureSlack(w *cli.Worker, so *internal.SlackOverflow) error {
 		return err
 	}
 	so.Config.Slack.SetTeamInfo(team)
+
+	w.Log.Line("Socket Mode lets SlackOverflow receive slash commands and button clicks without public HTTP endpoint.")
+	w.Log.Line("It requires an app-level token (xapp-...) with connections:write scope.")
+	if socketMode := w.AskForConfirmation("Do you want to enable Socket Mode?"); socketMode {
+		w.Log.Line("Enter your Slack app-level token")
+		appToken, _ := reader.ReadString('\n')
+		so.Config.Slack.SetAppToken(strings.TrimSpace(appToken))
+		so.Config.Slack.SocketMode = true
+	}
+	w.Log.Line("Enter Slack signing secret to verify requests received over HTTP (leave empty to skip)")
+	secret, _ := reader.ReadString('\n')
+	so.Config.Slack.SigningSecret = strings.TrimSpace(secret)
 	return so.Config.Save()
 }
 
@@ -141,7 +154,12 @@ func configureStackExchange(w *cli.Worker, so *internal.SlackOverflow) error {
 	w.Log.Line("You can also set multible tags separated with (;) e.g: aframe;three.js")
 
 	tagged, _ := reader.ReadString('\n')
-	so.Config.StackExchange.SearchAdvanced["tagged"] = strings.TrimSpace(tagged)
+	if tagged = strings.TrimSpace(tagged); tagged != "" {
+		so.Config.StackExchange.SearchAdvanced["tagged"] = tagged
+	} else {
+		// Empty query parameters are rejected
+		delete(so.Config.StackExchange.SearchAdvanced, "tagged")
+	}
 
 	// Number of questions to watch
 	w.Log.Line("Set the value for how many latest questions you want to track and update.")
@@ -150,6 +168,13 @@ func configureStackExchange(w *cli.Worker, so *internal.SlackOverflow) error {
 	w.Log.Line("Emoijs of these stats will be removed from older than (n) questions.")
 	fmt.Scan(&so.Config.StackExchange.QuestionsToWatch)
 
+	w.Log.Line("Question body excerpt with code snippets can be shown in Slack message.")
+	so.Config.StackExchange.QuestionBody = w.AskForConfirmation("Do you want to show question body excerpt?")
+	if so.Config.StackExchange.QuestionBody {
+		w.Log.Line("Set maximum length of the excerpt in characters e.g: 300")
+		fmt.Scan(&so.Config.StackExchange.BodyExcerptLength)
+	}
+
 	w.Log.Line("Without having Stack Exchange API APP key's you can make 300 requests per day.")
 	w.Log.Line("When you register for an Stack Exchange API App Key you can make 10000 requests per day")
 	w.Log.Line("You can register for an APP KEY here: http://stackapps.com/apps/oauth/register")
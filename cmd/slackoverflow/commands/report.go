@@ -0,0 +1,84 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/howi-ce/howi/std/errors"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+)
+
+// Report command for SlackOverflow.
+func Report(so *internal.SlackOverflow) cli.Command {
+	cmd := cli.NewCommand("report")
+	cmd.SetShortDesc("Reports based on collected data see slackoverflow report --help for more info.")
+	cmd.AddSubcommand(ReportLeaderboard(so))
+	return cmd
+}
+
+// ReportLeaderboard returns team leaderboard command
+func ReportLeaderboard(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("leaderboard")
+	scmd.SetShortDesc("Answers and accepted answers of linked Slack users.")
+
+	sinceFlag := flags.NewStringFlag("since")
+	sinceFlag.SetUsage("count answers created since e.g. 30d, 12h or 2017-01-01 (default 30d)")
+	scmd.AddFlag(sinceFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		sinceValue := "30d"
+		if since, _ := w.Flag("since"); since.Present() {
+			sinceValue = since.Value().String()
+		}
+		since, err := parseSince(sinceValue, time.Now().UTC())
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		board, err := so.DB.Leaderboard(since)
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		w.Log.Linef("Leaderboard since %s", since.Format("2006-01-02 15:04 MST"))
+		table := internal.NewTable("#", "Slack user", "Stack Exchange user", "Answers", "Accepted", "Score")
+		for i, row := range board {
+			name := row.DisplayName
+			if name == "" {
+				name = strconv.Itoa(row.UID)
+			}
+			table.AddRow(i+1, row.SlackUID, name, row.Answers, row.Accepted, row.Score)
+		}
+		table.Print()
+	})
+	return scmd
+}
+
+// parseSince parses relative duration with day suffix (30d), Go duration (12h)
+// or date (2017-01-01) into time relative to now
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return now, errors.Newf("invalid value %q expected e.g. 30d, 12h or 2017-01-01", value)
+}
@@ -5,8 +5,10 @@
 package commands
 
 import (
+	"context"
 	"os"
 	"os/signal"
+	"sync"
 
 	"github.com/howi-ce/howi/addon/application/plugin/cli"
 	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
@@ -33,14 +35,40 @@ func Run(so *internal.SlackOverflow) cli.Command {
 			w.Fail(err.Error())
 			return
 		}
-		runFull(w, so)
+		// runFull prints actions of dry run after each run
+		defer so.DryRun.Close()
+		runFull(w, so, nil)
 		if keepAlive.Present() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			so.StackExchange.SetContext(ctx)
+			defer so.StackExchange.CloseIdleConnections()
+			if so.Config.Slack.SocketMode {
+				go func() {
+					if err := startSlackListener(ctx, w, so, ""); err != nil {
+						w.Log.Error(err)
+					}
+				}()
+			}
+			var realtime *internal.StackExchangeRealtime
+			if so.Config.StackExchange.Realtime {
+				if realtime, err = so.StackExchangeRealtime(); err != nil {
+					w.Log.Error(err)
+				} else {
+					go func() {
+						if err := realtime.Run(ctx, w); err != nil {
+							w.Log.Error(err)
+						}
+					}()
+					go consumeRealtime(ctx, w, so, realtime)
+				}
+			}
 			cr := cron.New()
 			cr.AddFunc("@every 1m", func() {
-				runFull(w, so)
+				runFull(w, so, realtime)
 			})
 			go cr.Start()
-			sig := make(chan os.Signal)
+			sig := make(chan os.Signal, 1)
 			signal.Notify(sig, os.Interrupt, os.Kill)
 			<-sig
 		}
@@ -48,9 +76,41 @@ func Run(so *internal.SlackOverflow) cli.Command {
 	return cmd
 }
 
-func runFull(w *cli.Worker, so *internal.SlackOverflow) {
-	getNewQuestions(w, so)
+// runMu serializes full runs and questions received in real-time
+var runMu sync.Mutex
+
+// runFull syncs questions and posts to Slack, new questions are polled only
+// if real-time source is not connected
+func runFull(w *cli.Worker, so *internal.SlackOverflow, realtime *internal.StackExchangeRealtime) {
+	runMu.Lock()
+	defer runMu.Unlock()
+	if realtime.Connected() {
+		w.Log.Debug("Stack Exchange: real-time source connected, not polling new questions.")
+	} else {
+		getNewQuestions(w, so)
+	}
 	updateQuestions(w, so)
+	updateAnswers(w, so)
 	slackPostNewQuestions(w, so)
 	slackUpdateQuestions(w, so)
+	slackResurfaceSnoozed(w, so)
+	slackCreditAnswers(w, so)
+	refreshUsersIfDue(w, so)
+	if so.Config.Slack.InboxChannel != "" && so.Config.StackExchange.AccessToken != "" {
+		getInboxItems(w, so)
+		slackRelayInbox(w, so)
+	}
+	so.DryRun.Print(w)
+}
+
+// dryRunDone prints actions of dry run and discards database changes made
+// during it
+func dryRunDone(w *cli.Worker, so *internal.SlackOverflow) {
+	if !so.DryRun.Enabled() {
+		return
+	}
+	so.DryRun.Print(w)
+	if err := so.DryRun.Close(); err != nil {
+		w.Log.Error(err)
+	}
 }
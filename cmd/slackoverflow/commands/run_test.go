@@ -0,0 +1,126 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal/fakese"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// TestRunFullRecordReplay records two runs against fake APIs, replays them
+// with empty database and compares both to testdata/run-cycle.golden
+func TestRunFullRecordReplay(t *testing.T) {
+	env := newTestEnv(t, fakese.Options{Seed: 1})
+	defer env.Close()
+
+	dir := filepath.Join(env.dir, "recording")
+	recorder, err := internal.NewRecordingTransport(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.so.SetHTTPTransport(recorder)
+	// First run syncs only latest question, second one posts new questions
+	runFull(env.w, env.so, nil)
+	env.se.AddQuestion()
+	env.se.AddQuestion()
+	runFull(env.w, env.so, nil)
+	recorded := runCycleState(t, env.so)
+	if n := len(env.slack.Messages(testChannel)); n != 3 {
+		t.Errorf("%d messages posted, want 3", n)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests []string
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{testSlackToken, testStackExchangeKey} {
+			if bytes.Contains(data, []byte(secret)) {
+				t.Errorf("%s: secret %q is not redacted", filepath.Base(file), secret)
+			}
+		}
+		requests = append(requests, recordedRequest(t, data))
+	}
+
+	// Servers are closed so that every response must come from recording
+	env.se.Close()
+	env.slack.Close()
+	replay, err := internal.NewReplayTransport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	so := newTestSlackOverflow(t, filepath.Join(env.dir, "replay.db3"), env.se.URL(), env.slack.URL())
+	defer so.DB.Close()
+	so.SetHTTPTransport(replay)
+	runFull(env.w, so, nil)
+	runFull(env.w, so, nil)
+	if n := replay.Remaining(); n != 0 {
+		t.Errorf("%d recorded responses not replayed", n)
+	}
+	if replayed := runCycleState(t, so); replayed != recorded {
+		t.Errorf("replayed state differs from recorded\nrecorded:\n%s\nreplayed:\n%s", recorded, replayed)
+	}
+
+	got := "# requests\n" + strings.Join(requests, "\n") + "\n# state\n" + recorded
+	golden := filepath.Join("testdata", "run-cycle.golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("run cycle differs from %s, run with -update if change is expected\ngot:\n%s\nwant:\n%s", golden, got, want)
+	}
+}
+
+// runCycleState returns posted questions as lines of text, timestamps of
+// messages are left out since they have time of the run
+func runCycleState(t *testing.T, so *internal.SlackOverflow) string {
+	links, _ := so.DB.SlackQuestionGetAll()
+	var lines []string
+	for _, link := range links {
+		q := so.DB.FindStackExchangeQuestion(link.QID)
+		lines = append(lines, fmt.Sprintf("%d %s %q answers=%d", link.QID, link.Channel, q.Title, q.AnswerCount))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// recordedRequest returns method and URL path of recorded exchange, query
+// is left out since it has dates of the run
+func recordedRequest(t *testing.T, data []byte) string {
+	var exchange struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+	}
+	if err := json.Unmarshal(data, &exchange); err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(exchange.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return exchange.Method + " " + u.Path
+}
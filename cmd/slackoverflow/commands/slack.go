@@ -5,7 +5,12 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"html"
+	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/howi-ce/howi/addon/application/plugin/cli"
 	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
@@ -16,6 +21,7 @@ import (
 const (
 	msgNotAnswered = "#B7E0ED"
 	msgIsAnswewed  = "#30AC1F"
+	msgInactive    = "#9E9E9E"
 	thumbUp        = ":+1:"
 	thumbDown      = ":-1:"
 )
@@ -26,6 +32,9 @@ func Slack(so *internal.SlackOverflow) cli.Command {
 	cmd.SetShortDesc("Slack related commands see slackoverflow slack --help for more info.")
 	cmd.AddSubcommand(SlackChannels(so))
 	cmd.AddSubcommand(SlackQuestions(so))
+	cmd.AddSubcommand(SlackListen(so))
+	cmd.AddSubcommand(SlackLink(so))
+	cmd.AddSubcommand(SlackSubscribe(so))
 	return cmd
 }
 
@@ -38,7 +47,7 @@ func SlackChannels(so *internal.SlackOverflow) cli.Command {
 			w.Fail(err.Error())
 			return
 		}
-		api := slack.New(so.Config.Slack.Token)
+		api := so.SlackClient()
 		channels, err := api.GetChannels(true)
 		if err != nil {
 			w.Fail(err.Error())
@@ -114,8 +123,56 @@ func SlackQuestions(so *internal.SlackOverflow) cli.Command {
 			return
 		}
 	})
+	scmd.AfterAlways(func(w *cli.Worker) {
+		dryRunDone(w, so)
+	})
+	return scmd
+}
+
+// SlackListen returns command receiving Slack slash commands and interactive payloads
+func SlackListen(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("listen")
+	scmd.SetShortDesc("Receive Slack slash commands, events and interactive payloads using Socket Mode.")
+
+	httpFlag := flags.NewStringFlag("http")
+	httpFlag.SetUsage("serve HTTP endpoints on given address e.g. :8080 instead of connecting with Socket Mode")
+	scmd.AddFlag(httpFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		httpAddr, err := w.Flag("http")
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		addr := ""
+		if httpAddr.Present() {
+			addr = httpAddr.Value().String()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		errs := make(chan error, 1)
+		go func() {
+			errs <- startSlackListener(ctx, w, so, addr)
+		}()
+
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		select {
+		case <-sig:
+		case err := <-errs:
+			if err != nil {
+				w.Fail(err.Error())
+			}
+		}
+	})
 	return scmd
 }
+
 func slackPostNewQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 	w.Log.Info("Slack: Posting new questions.")
 
@@ -128,6 +185,10 @@ func slackPostNewQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 
 	// Process questions
 	for _, question := range tracked {
+		if question.Backfilled {
+			w.Log.Debugf("Slack: Question %d was backfilled, not posting", question.QID)
+			continue
+		}
 		slackQuestion := so.DB.FindSlackQuestion(question.QID)
 		if slackQuestion.QID == 0 {
 			user := so.DB.FindStackExchangeUser(question.UID)
@@ -145,35 +206,9 @@ func slackPostNewQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 			params.Markdown = true
 			params.EscapeText = true
 
-			color := msgNotAnswered
-			if question.IsAnswered {
-				color = msgIsAnswewed
-			}
-			thumb := thumbUp
-			if question.Score < 0 {
-				thumb = thumbDown
-			}
-			ficon := "https://aframe.io/images/aframe-logo-192.png"
-			if val, ok := so.Config.Slack.TeamInfo.Icon["image_132"].(string); ok {
-				ficon = val
-			}
-			attachment := slack.Attachment{
-				Fallback:  question.Title,
-				Title:     question.Title,
-				TitleLink: question.ShareLink,
-				Color:     color,
-				Text: fmt.Sprintf(":pencil: %d :speech_balloon: %d %s %d :eye: %d",
-					question.AnswerCount,
-					question.CommentCount,
-					thumb,
-					question.Score,
-					question.ViewCount,
-				),
-				Footer:     "slackoverflow",
-				FooterIcon: ficon,
-			}
+			attachment := slackQuestionAttachment(so, question)
 			params.Attachments = []slack.Attachment{attachment}
-			api := slack.New(so.Config.Slack.Token)
+			api := so.SlackClient()
 			channelID, timestamp, err := api.PostMessage(so.Config.Slack.Channel, "", params)
 			if err != nil {
 				w.Log.Error(err.Error())
@@ -189,6 +224,7 @@ func slackPostNewQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 			} else {
 				w.Log.Infof("Slack channel (%s): %s and question posted", channelID, msg)
 			}
+			slackNotifyNewQuestion(w, so, question)
 		} else {
 			w.Log.Debugf("Slack: Question %d already exists", question.QID)
 		}
@@ -204,54 +240,30 @@ func slackUpdateQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 		return
 	}
 
+	subs, err := so.DB.SlackSubscriptionsByKind(internal.SubscriptionQuestion)
+	if err != nil {
+		w.Log.Error(err)
+	}
+
 	track := 0
 	for _, ql := range links {
 		stackQuestion := so.DB.FindStackExchangeQuestion(ql.QID)
 		if stackQuestion.QID == 0 {
-			w.Log.Warning("Could not find question with ID: %d.", ql.QID)
+			w.Log.Warningf("Could not find question with ID: %d.", ql.QID)
 			continue
 		}
 		track++
 		if track <= so.Config.StackExchange.QuestionsToWatch {
-			color := msgNotAnswered
-			if stackQuestion.IsAnswered {
-				color = msgIsAnswewed
-			}
-			thumb := thumbUp
-			if stackQuestion.Score < 0 {
-				thumb = thumbDown
-			}
-			ficon := "https://aframe.io/images/aframe-logo-192.png"
-			if val, ok := so.Config.Slack.TeamInfo.Icon["image_132"].(string); ok {
-				ficon = val
-			}
-			attachment := slack.Attachment{
-				Fallback:  stackQuestion.Title,
-				Title:     stackQuestion.Title,
-				TitleLink: stackQuestion.ShareLink,
-				Color:     color,
-				Text: fmt.Sprintf(":pencil: %d :speech_balloon: %d %s %d :eye: %d",
-					stackQuestion.AnswerCount,
-					stackQuestion.CommentCount,
-					thumb,
-					stackQuestion.Score,
-					stackQuestion.ViewCount,
-				),
-				Footer:     "slackoverflow",
-				FooterIcon: ficon,
-			}
+			attachment := slackQuestionAttachment(so, stackQuestion)
 
-			api := slack.New(so.Config.Slack.Token)
-			channelID, _, _, err := api.SendMessage(so.Config.Slack.Channel,
-				slack.MsgOptionUpdate(ql.TS),
-				slack.MsgOptionAsUser(false),
-				slack.MsgOptionAttachments(attachment),
-			)
+			api := so.SlackClient()
+			channelID, err := api.UpdateMessage(so.Config.Slack.Channel, ql.TS, attachment)
 			if err != nil {
 				w.Log.Errorf("Slack channel (%s): %s", channelID, err.Error())
 			} else {
 				w.Log.Infof("Slack channel (%s) updated: %s", channelID, stackQuestion.Title)
 			}
+			slackNotifyQuestionChanges(w, so, subs, stackQuestion)
 
 		} else {
 			color := msgNotAnswered
@@ -264,14 +276,12 @@ func slackUpdateQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 				TitleLink: stackQuestion.ShareLink,
 				Color:     color,
 			}
-			api := slack.New(so.Config.Slack.Token)
-			channelID, _, _, err := api.SendMessage(so.Config.Slack.Channel,
-				slack.MsgOptionUpdate(ql.TS),
-				slack.MsgOptionAsUser(false),
-				slack.MsgOptionAttachments(attachment),
-			)
+			api := so.SlackClient()
+			channelID, err := api.ArchiveMessage(so.Config.Slack.Channel, ql.TS, attachment)
+			so.DryRun.Add("delete", "", fmt.Sprintf("question %d: %s", stackQuestion.QID, stackQuestion.Title))
 			so.DB.StackExchangeQuestionDelete(stackQuestion)
 			so.DB.SlackQuestionDelete(ql)
+			so.DB.SlackQuestionTriageDelete(ql.QID)
 			if err != nil {
 				w.Log.Errorf("Slack channel (%s): %s", channelID, err.Error())
 			} else {
@@ -279,4 +289,124 @@ func slackUpdateQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 			}
 		}
 	}
+	slackPostQuestionEdits(w, so)
+}
+
+// slackPostQuestionEdits posts detected title and tag changes to question thread
+func slackPostQuestionEdits(w *cli.Worker, so *internal.SlackOverflow) {
+	edits, err := so.DB.StackExchangeQuestionEditsNotPosted()
+	if err != nil {
+		w.Log.Error(err)
+		return
+	}
+	api := so.SlackClient()
+	for _, edit := range edits {
+		link := so.DB.FindSlackQuestion(edit.QID)
+		if link.QID > 0 {
+			params := slack.NewPostMessageParameters()
+			params.AsUser = false
+			params.ThreadTimestamp = link.TS
+			if _, _, err := api.PostMessage(link.Channel, questionEditText(edit), params); err != nil {
+				w.Log.Errorf("Slack channel (%s): %s", link.Channel, err.Error())
+				continue
+			}
+			w.Log.Infof("Slack: posted edit of question %d", edit.QID)
+		}
+		if err := so.DB.StackExchangeQuestionEditPosted(edit.ID); err != nil {
+			w.Log.Error(err)
+		}
+	}
+}
+
+// questionEditText describes the edit
+func questionEditText(edit internal.StackExchangeQuestionEdit) string {
+	lines := []string{":pencil2: Question was edited"}
+	if edit.Editor != "" {
+		lines[0] += " by " + edit.Editor
+	}
+	if edit.NewTitle != "" {
+		lines = append(lines, fmt.Sprintf("Title: ~%s~ → %s", edit.OldTitle, edit.NewTitle))
+	}
+	if len(edit.TagsAdded) > 0 {
+		lines = append(lines, "Tags added: `"+strings.Join(edit.TagsAdded, "` `")+"`")
+	}
+	if len(edit.TagsRemoved) > 0 {
+		lines = append(lines, "Tags removed: `"+strings.Join(edit.TagsRemoved, "` `")+"`")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// slackQuestionAttachment renders question with its triage state and buttons
+func slackQuestionAttachment(so *internal.SlackOverflow, question internal.StackExchangeQuestion) slack.Attachment {
+	color := msgNotAnswered
+	if question.IsAnswered {
+		color = msgIsAnswewed
+	}
+	thumb := thumbUp
+	if question.Score < 0 {
+		thumb = thumbDown
+	}
+	ficon := "https://aframe.io/images/aframe-logo-192.png"
+	if val, ok := so.Config.Slack.TeamInfo.Icon["image_132"].(string); ok {
+		ficon = val
+	}
+	attachment := slack.Attachment{
+		Fallback:  question.Title,
+		Title:     question.Title,
+		TitleLink: question.ShareLink,
+		Color:     color,
+		Text: fmt.Sprintf(":pencil: %d :speech_balloon: %d %s %d :eye: %d",
+			question.AnswerCount,
+			question.CommentCount,
+			thumb,
+			question.Score,
+			question.ViewCount,
+		),
+		Footer:     "slackoverflow",
+		FooterIcon: ficon,
+	}
+	if question.Lifecycle != "" && question.Lifecycle != internal.LifecycleOpen {
+		// Closed, deleted and migrated questions need no attention anymore
+		attachment.Title = ""
+		attachment.TitleLink = ""
+		attachment.Color = msgInactive
+		attachment.MarkdownIn = []string{"text"}
+		attachment.Text = fmt.Sprintf("~%s~\n%s",
+			internal.MrkdwnLink(question.ShareLink, html.UnescapeString(question.Title)), lifecycleStatus(question))
+		return attachment
+	}
+	if so.Config.StackExchange.QuestionBody && question.BodyMarkdown != "" {
+		// Excerpt with code snippets above stats
+		attachment.Text = internal.MarkdownToMrkdwn(question.BodyMarkdown,
+			so.Config.StackExchange.ExcerptLength()) + "\n" + attachment.Text
+		attachment.MarkdownIn = []string{"text"}
+	}
+	if question.RetaggedAway {
+		attachment.Color = msgInactive
+		attachment.Text += "\n:label: retagged away from tracked tags"
+		return attachment
+	}
+	triage := so.DB.FindSlackQuestionTriage(question.QID)
+	if status := triageStatus(triage); status != "" {
+		attachment.Text += "\n" + status
+	}
+	attachment.CallbackID = triageCallbackID
+	attachment.Actions = triageActions(question.QID, triage)
+	return attachment
+}
+
+// lifecycleStatus describes why question is not open anymore
+func lifecycleStatus(question internal.StackExchangeQuestion) string {
+	switch question.Lifecycle {
+	case internal.LifecycleClosed:
+		if question.ClosedReason != "" {
+			return fmt.Sprintf(":lock: closed as %s", question.ClosedReason)
+		}
+		return ":lock: closed"
+	case internal.LifecycleDeleted:
+		return ":wastebasket: deleted"
+	case internal.LifecycleMigrated:
+		return fmt.Sprintf(":airplane: migrated to %s", question.MigratedTo)
+	}
+	return ""
 }
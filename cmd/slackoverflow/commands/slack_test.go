@@ -0,0 +1,184 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal/fakese"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal/fakeslack"
+)
+
+func TestSlackPostNewQuestions(t *testing.T) {
+	env := newTestEnv(t, fakese.Options{Seed: 1})
+	defer env.Close()
+	questions := addQuestions(t, env, 2)
+
+	// Failed post is not stored and is retried on next run
+	env.slack.FailNext("chat.postMessage", "rate_limited")
+	slackPostNewQuestions(env.w, env.so)
+	if links, count := env.so.DB.SlackQuestionGetAll(); count != 0 {
+		t.Fatalf("%d questions stored as posted after failed post: %v", count, links)
+	}
+
+	slackPostNewQuestions(env.w, env.so)
+	messages := env.slack.Messages(testChannel)
+	if len(messages) != len(questions) {
+		t.Fatalf("%d messages posted, want %d", len(messages), len(questions))
+	}
+	for _, q := range questions {
+		link := env.so.DB.FindSlackQuestion(q.QID)
+		if link.QID != q.QID || link.Channel != testChannel {
+			t.Errorf("question %d stored as posted to %q, want %q", q.QID, link.Channel, testChannel)
+			continue
+		}
+		m, ok := findMessage(messages, link.TS)
+		if !ok {
+			t.Errorf("question %d stored with ts %s which was not posted", q.QID, link.TS)
+			continue
+		}
+		for _, want := range []string{q.Title, q.ShareLink} {
+			if !strings.Contains(m.Attachments, want) {
+				t.Errorf("message of question %d does not contain %q: %s", q.QID, want, m.Attachments)
+			}
+		}
+	}
+
+	// Posted questions are not posted again
+	slackPostNewQuestions(env.w, env.so)
+	if n := len(env.slack.Messages(testChannel)); n != len(questions) {
+		t.Errorf("%d messages after second run, want %d", n, len(questions))
+	}
+}
+
+func TestSlackUpdateQuestions(t *testing.T) {
+	env := newTestEnv(t, fakese.Options{Seed: 1})
+	defer env.Close()
+	questions := addQuestions(t, env, 2)
+	slackPostNewQuestions(env.w, env.so)
+
+	q := questions[0]
+	env.se.AddAnswer(q.QID)
+	env.se.EditQuestion(q.QID, func(q *internal.QuestionObj) {
+		q.Title = "How to test updates?"
+	})
+	updateQuestions(env.w, env.so)
+	slackUpdateQuestions(env.w, env.so)
+
+	messages := env.slack.Messages(testChannel)
+	for _, question := range questions {
+		link := env.so.DB.FindSlackQuestion(question.QID)
+		if m, ok := findMessage(messages, link.TS); !ok || m.Updated != 1 {
+			t.Errorf("message of question %d updated %d times, want 1", question.QID, m.Updated)
+		}
+	}
+	link := env.so.DB.FindSlackQuestion(q.QID)
+	m, _ := findMessage(messages, link.TS)
+	stored := env.so.DB.FindStackExchangeQuestion(q.QID)
+	for _, want := range []string{"How to test updates?", fmt.Sprintf(":pencil: %d ", stored.AnswerCount)} {
+		if !strings.Contains(m.Attachments, want) {
+			t.Errorf("updated message does not contain %q: %s", want, m.Attachments)
+		}
+	}
+	if stored.AnswerCount != q.AnswerCount+1 {
+		t.Errorf("question has %d answers, want %d", stored.AnswerCount, q.AnswerCount+1)
+	}
+
+	// Edit is posted to thread of question once
+	var replies []fakeslack.Call
+	for _, call := range env.slack.Calls("chat.postMessage") {
+		if call.Params.Get("thread_ts") != "" {
+			replies = append(replies, call)
+		}
+	}
+	if len(replies) != 1 {
+		t.Fatalf("%d thread replies posted, want 1", len(replies))
+	}
+	if ts := replies[0].Params.Get("thread_ts"); ts != link.TS {
+		t.Errorf("edit posted to thread %s, want %s", ts, link.TS)
+	}
+	if text := replies[0].Params.Get("text"); !strings.Contains(text, "How to test updates?") {
+		t.Errorf("edit reply does not contain new title: %s", text)
+	}
+	if edits, _ := env.so.DB.StackExchangeQuestionEditsNotPosted(); len(edits) != 0 {
+		t.Errorf("%d edits not marked as posted", len(edits))
+	}
+}
+
+func TestSlackUpdateQuestionsArchivesUntracked(t *testing.T) {
+	env := newTestEnv(t, fakese.Options{Seed: 1})
+	defer env.Close()
+	addQuestions(t, env, 2)
+	slackPostNewQuestions(env.w, env.so)
+	// Links are latest posted first, older one is not tracked anymore
+	links, count := env.so.DB.SlackQuestionGetAll()
+	if count != 2 {
+		t.Fatalf("%d questions posted, want 2", count)
+	}
+	kept, archived := links[0], links[1]
+
+	env.so.Config.StackExchange.QuestionsToWatch = 1
+	slackUpdateQuestions(env.w, env.so)
+
+	if link := env.so.DB.FindSlackQuestion(archived.QID); link.QID != 0 {
+		t.Errorf("question %d still tracked in Slack", archived.QID)
+	}
+	if stored := env.so.DB.FindStackExchangeQuestion(archived.QID); stored.QID != 0 {
+		t.Errorf("question %d still stored", archived.QID)
+	}
+	if link := env.so.DB.FindSlackQuestion(kept.QID); link.QID != kept.QID {
+		t.Errorf("question %d not tracked in Slack anymore", kept.QID)
+	}
+	m, ok := findMessage(env.slack.Messages(testChannel), archived.TS)
+	if !ok {
+		t.Fatalf("message of question %d not found", archived.QID)
+	}
+	if m.Updated != 1 || strings.Contains(m.Attachments, ":pencil:") {
+		t.Errorf("message of question %d not archived: %s", archived.QID, m.Attachments)
+	}
+}
+
+func TestSlackQuestionAttachmentEscapesInactiveTitle(t *testing.T) {
+	env := newTestEnv(t, fakese.Options{Seed: 1})
+	defer env.Close()
+
+	attachment := slackQuestionAttachment(env.so, internal.StackExchangeQuestion{
+		QID:       1,
+		Title:     "a | b &lt;c&gt;",
+		ShareLink: "https://stackoverflow.com/q/1",
+		Lifecycle: internal.LifecycleDeleted,
+	})
+	want := "~<https://stackoverflow.com/q/1|a / b &lt;c&gt;>~\n"
+	if !strings.HasPrefix(attachment.Text, want) {
+		t.Errorf("attachment text %q, want prefix %q", attachment.Text, want)
+	}
+}
+
+// addQuestions creates questions in fake Stack Exchange and stores them,
+// oldest first
+func addQuestions(t *testing.T, env *testEnv, n int) []internal.QuestionObj {
+	var questions []internal.QuestionObj
+	for i := 0; i < n; i++ {
+		q := env.se.AddQuestion()
+		if !env.so.SyncQuestion(env.w, q) {
+			t.Fatalf("question %d was not stored", q.QID)
+		}
+		questions = append(questions, q)
+	}
+	return questions
+}
+
+// findMessage posted at ts
+func findMessage(messages []fakeslack.Message, ts string) (fakeslack.Message, bool) {
+	for _, m := range messages {
+		if m.TS == ts {
+			return m, true
+		}
+	}
+	return fakeslack.Message{}, false
+}
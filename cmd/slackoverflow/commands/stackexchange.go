@@ -5,14 +5,19 @@
 package commands
 
 import (
+	"encoding/json"
+	"fmt"
+	"html"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/howi-ce/howi/addon/application/plugin/cli"
 	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/howi-ce/howi/std/errors"
 	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
-	"github.com/robfig/cron"
 )
 
 var fromDate time.Time
@@ -23,6 +28,12 @@ func StackExchange(so *internal.SlackOverflow) cli.Command {
 	cmd.SetShortDesc("Stack Exchange related commands see slackoverflow stackexchange --help for more info.")
 	cmd.AddSubcommand(StackExchangeQuestions(so))
 	cmd.AddSubcommand(StackExchangeWatch(so))
+	cmd.AddSubcommand(StackExchangeFilter(so))
+	cmd.AddSubcommand(StackExchangeLogin(so))
+	cmd.AddSubcommand(StackExchangeInbox(so))
+	cmd.AddSubcommand(StackExchangeUsers(so))
+	cmd.AddSubcommand(StackExchangeTags(so))
+	cmd.AddSubcommand(StackExchangeBackfill(so))
 
 	return cmd
 }
@@ -69,6 +80,7 @@ func StackExchangeQuestions(so *internal.SlackOverflow) cli.Command {
 		if sync.Present() {
 			getNewQuestions(w, so)
 			updateQuestions(w, so)
+			updateAnswers(w, so)
 		} else if get.Present() {
 			getNewQuestions(w, so)
 		} else if update.Present() {
@@ -87,6 +99,7 @@ func StackExchangeQuestions(so *internal.SlackOverflow) cli.Command {
 			so.StackExchange.GetQuotaRemaining(),
 			so.StackExchange.GetQuotaMax(),
 		)
+		dryRunDone(w, so)
 	})
 	return scmd
 }
@@ -94,23 +107,54 @@ func StackExchangeQuestions(so *internal.SlackOverflow) cli.Command {
 // StackExchangeWatch ...
 func StackExchangeWatch(so *internal.SlackOverflow) cli.Command {
 	scmd := cli.NewCommand("watch")
-	scmd.SetShortDesc("Watch new questions from Stack Exchange site (updated every minute nothing stored to db or posted to slack)")
+	scmd.SetShortDesc("Watch new questions from Stack Exchange site (nothing stored to db or posted to slack)")
+
+	taggedFlag := flags.NewStringFlag("tagged")
+	taggedFlag.SetUsage("semicolon delimited tags to watch (default: search-advanced.tagged from config)")
+	scmd.AddFlag(taggedFlag)
+
+	siteFlag := flags.NewStringFlag("site")
+	siteFlag.SetUsage("Stack Exchange site to watch (default: site from config)")
+	scmd.AddFlag(siteFlag)
+
+	minScoreFlag := flags.NewStringFlag("min-score")
+	minScoreFlag.SetUsage("show only questions with at least this score")
+	scmd.AddFlag(minScoreFlag)
+
+	intervalFlag := flags.NewStringFlag("interval")
+	intervalFlag.SetUsage("how often to check for new questions e.g. 30s, 5m (default: 1m)")
+	scmd.AddFlag(intervalFlag)
+
+	formatFlag := flags.NewStringFlag("format")
+	formatFlag.SetUsage("output format table, json or line (default: table)")
+	scmd.AddFlag(formatFlag)
+
 	scmd.Do(func(w *cli.Worker) {
 		if err := so.Session(w); err != nil {
 			w.Fail(err.Error())
 			return
 		}
-		w.Log.Ok("Waiting for new questions!")
+		opts, err := newWatchOptions(w)
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		w.Log.Okf("Waiting for new questions! Checking every %s.", opts.interval)
 		fromDate = time.Now().UTC().Add(-30 * time.Minute)
-		startWatching(w, so)
-		watch := cron.New()
-		watch.AddFunc("@every 1m", func() {
-			startWatching(w, so)
-		})
-		go watch.Start()
-		sig := make(chan os.Signal)
-		signal.Notify(sig, os.Interrupt, os.Kill)
-		<-sig
+		startWatching(w, so, opts)
+		ticker := time.NewTicker(opts.interval)
+		defer ticker.Stop()
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		defer signal.Stop(sig)
+		for {
+			select {
+			case <-ticker.C:
+				startWatching(w, so, opts)
+			case <-sig:
+				return
+			}
+		}
 	})
 	scmd.AfterAlways(func(w *cli.Worker) {
 		if err := so.DB.Close(); err != nil {
@@ -125,48 +169,123 @@ func StackExchangeWatch(so *internal.SlackOverflow) cli.Command {
 	return scmd
 }
 
+const (
+	watchFormatTable     = "table"
+	watchFormatJSON      = "json"
+	watchFormatLine      = "line"
+	watchMinInterval     = 10 * time.Second
+	quotaGaugeWidth      = 20
+	watchDefaultInterval = time.Minute
+)
+
+// watchOptions of stackexchange watch command
+type watchOptions struct {
+	tagged   string
+	site     string
+	minScore int
+	hasMin   bool
+	interval time.Duration
+	format   string
+}
+
+// newWatchOptions reads and validates watch flags
+func newWatchOptions(w *cli.Worker) (watchOptions, error) {
+	opts := watchOptions{
+		interval: watchDefaultInterval,
+		format:   watchFormatTable,
+	}
+	if f, _ := w.Flag("tagged"); f.Present() {
+		opts.tagged = f.Value().String()
+	}
+	if f, _ := w.Flag("site"); f.Present() {
+		opts.site = f.Value().String()
+	}
+	if f, _ := w.Flag("min-score"); f.Present() {
+		score, err := strconv.Atoi(f.Value().String())
+		if err != nil {
+			return opts, errors.Newf("--min-score must be a number, got %q", f.Value().String())
+		}
+		opts.minScore = score
+		opts.hasMin = true
+	}
+	if f, _ := w.Flag("interval"); f.Present() {
+		interval, err := time.ParseDuration(f.Value().String())
+		if err != nil {
+			return opts, errors.Newf("--interval must be a duration e.g. 30s or 5m, got %q", f.Value().String())
+		}
+		if interval < watchMinInterval {
+			return opts, errors.Newf("--interval must be at least %s", watchMinInterval)
+		}
+		opts.interval = interval
+	}
+	if f, _ := w.Flag("format"); f.Present() {
+		opts.format = f.Value().String()
+	}
+	switch opts.format {
+	case watchFormatTable, watchFormatJSON, watchFormatLine:
+	default:
+		return opts, errors.Newf("--format must be %s, %s or %s, got %q",
+			watchFormatTable, watchFormatJSON, watchFormatLine, opts.format)
+	}
+	return opts, nil
+}
+
 func getNewQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 	w.Log.Info("Stack Exchange: Checking for new questions.")
 
+	feed := newQuestionsFeed(so)
+	cursor := so.DB.FindStackExchangeSyncCursor(feed)
 	var empty bool
-	latest, _ := so.DB.LatestStackExchangeQuestion()
-	if latest.QID == 0 {
-		empty = true
-	}
-
-	if empty {
-		w.Log.Info("There are no questions in database,")
-		w.Log.Info("That is ok if current execution is first time you run slackoverflow")
-		w.Log.Infof("Or there has been no questions tagged with %q on site %q",
-			so.Config.StackExchange.SearchAdvanced["tagged"],
-			so.Config.StackExchange.Site,
-		)
-		latest.CreationDate = time.Now().UTC().Add(-4 * time.Hour)
+	if cursor.Feed == "" {
+		// Continue from latest stored question when cursor does not exist yet
+		latest, _ := so.DB.LatestStackExchangeQuestion()
+		if latest.QID == 0 {
+			empty = true
+			w.Log.Info("There are no questions in database,")
+			w.Log.Info("That is ok if current execution is first time you run slackoverflow")
+			w.Log.Infof("Or there has been no questions tagged with %q on site %q",
+				so.Config.StackExchange.SearchAdvanced["tagged"],
+				so.Config.StackExchange.Site,
+			)
+		}
+		cursor = internal.StackExchangeSyncCursor{
+			Feed:             feed,
+			LastCreationDate: latest.CreationDate,
+		}
 	}
 
-	now := time.Now().UTC().Unix()
-	diff := latest.CreationDate.Unix() - now
-	// Allow maximum 4 h old question as from date otherwise we may exhaust
-	// rate limit if last tracked questions fromdata is to far past
-	// and slackoverflow has not been running for a while.
-	if diff > (4 * 60) {
-		latest.CreationDate = time.Now().UTC().Add(-4 * time.Hour)
+	// Clamp from date to max lookback otherwise we may exhaust rate limit
+	// if slackoverflow has not been running for a while.
+	lookback := so.Config.StackExchange.MaxLookbackDuration()
+	since := cursor.LastCreationDate
+	if oldest := time.Now().UTC().Add(-lookback); since.Before(oldest) {
+		if !empty {
+			w.Log.Warningf("Stack Exchange: Last new question was created %s, skipping questions older than %s.",
+				since.Local().Format("2006-01-02 15:04"), lookback)
+		}
+		since = oldest
 	}
 
-	w.Log.Infof("Checking new questions since %s", latest.CreationDate.String())
+	w.Log.Infof("Checking new questions since %s", since.String())
 
 	// Check for New Questions from Stack Exchange
-	searchAdvanced := so.StackExchange.SearchAdvanced()
-
-	// Set it here so that it is allowed to override by config
-	searchAdvanced.Parameters.Set("site", so.Config.StackExchange.Site)
-
-	// Set all parameters from config
-	for param, value := range so.Config.StackExchange.SearchAdvanced {
-		searchAdvanced.Parameters.Set(param, value)
+	so.QuestionFilter(w)
+	searchAdvanced, err := so.SearchAdvanced()
+	if err != nil {
+		w.Log.Error(err)
+		return
+	}
+	// Oldest first so that cursor can be advanced even if paging stops early
+	for param, value := range map[string]interface{}{
+		"fromdate": since.Unix() + 1,
+		"sort":     "creation",
+		"order":    "asc",
+	} {
+		if err = searchAdvanced.Parameters.Set(param, value); err != nil {
+			w.Log.Error(err)
+			return
+		}
 	}
-
-	searchAdvanced.Parameters.Set("fromdate", latest.CreationDate.Unix()+1)
 
 	// Output query as table
 	d, _ := w.Flag("debug")
@@ -175,48 +294,68 @@ func getNewQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 		searchAdvanced.DrawQuery(w)
 	}
 
-	fetchQuestions := true
+	// Questions are oldest first, cursor is advanced only up to last
+	// question stored before first failure so that failed ones are retried
+	synced := cursor.LastCreationDate
+	failed := false
+	advance := func(q internal.QuestionObj) {
+		if created := time.Unix(q.CreationDate, 0).UTC(); !failed && created.After(synced) {
+			synced = created
+		}
+	}
 	var lastQuestion internal.QuestionObj
-	for fetchQuestions {
-		w.Log.Debugf("Fetching page %d", searchAdvanced.GetCurrentPageNr())
-		if results, err := searchAdvanced.Get(); results {
-			// Questions received
-			for _, q := range searchAdvanced.Result.Items {
+	pager := so.Pager(searchAdvanced.Pages())
+	for pager.Next() {
+		// Questions received
+		for _, q := range searchAdvanced.Result.Items {
 
-				w.Log.Infof("Question: %s", q.Title)
-				w.Log.Infof("Url:      %s", q.ShareLink)
+			w.Log.Infof("Question: %s", q.Title)
+			w.Log.Infof("Url:      %s", q.ShareLink)
 
-				if debbuging {
-					printQuestion(q)
-				}
-				// Skip sync if there are locally no questions
-				if empty {
-					lastQuestion = q
-					continue
-				}
-				so.SyncQuestion(w, q)
+			if debbuging {
+				printQuestion(q)
+			}
+			// Skip sync if there are locally no questions
+			if empty {
+				lastQuestion = q
+				continue
 			}
-			if err != nil {
-				fetchQuestions = false
-				w.Log.Error(err)
+			if so.SyncQuestion(w, q) {
+				advance(q)
+			} else {
+				failed = true
 			}
 		}
+	}
+	logPager(w, so, pager)
+	if err := pager.Err(); err != nil {
+		w.Log.Warning("Stack Exchange: sync cursor not advanced, new questions are requested again on next run.")
+		return
+	}
+	if empty && lastQuestion.QID > 0 && so.SyncQuestion(w, lastQuestion) {
+		advance(lastQuestion)
+	}
+	cursor.LastCreationDate = synced
 
-		// Done go to next page
-		if searchAdvanced.GetCurrentPageNr() > 10 {
-			fetchQuestions = false
-		} else if searchAdvanced.HasMore() {
-			searchAdvanced.NextPage()
-		} else {
-			fetchQuestions = false
-			w.Log.Debug("There are no more new questions.")
-		}
+	// Questions before clamped from date are skipped for good
+	if cursor.LastCreationDate.Before(since) {
+		cursor.LastCreationDate = since
 	}
-	if empty && lastQuestion.QID > 0 {
-		so.SyncQuestion(w, lastQuestion)
+	cursor.LastRun = time.Now().UTC()
+	cursor.LastPages = pager.Page()
+	cursor.LastItems = pager.Items()
+	if err := so.DB.StackExchangeSyncCursorAdvance(cursor); err != nil {
+		w.Log.Error(err)
 	}
 }
 
+// newQuestionsFeed returns sync cursor key of new questions of configured
+// site and tags
+func newQuestionsFeed(so *internal.SlackOverflow) string {
+	return fmt.Sprintf("search-advanced|%s|%s",
+		so.Config.StackExchange.Site, so.Config.StackExchange.SearchAdvanced["tagged"])
+}
+
 func updateQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 	w.Log.Info("Stack Exchange: Updating existing questions.")
 
@@ -234,13 +373,11 @@ func updateQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 		questionIdsCount, so.Config.StackExchange.QuestionsToWatch)
 
 	// Check for New Questions from Stack Exchange
-	updateQuestions := so.StackExchange.Questions()
-
-	// Set it here so that it is allowed to override by config
-	updateQuestions.Parameters.Set("site", so.Config.StackExchange.Site)
-
-	for param, value := range so.Config.StackExchange.Questions {
-		updateQuestions.Parameters.Set(param, value)
+	so.QuestionFilter(w)
+	updateQuestions, err := so.Questions()
+	if err != nil {
+		w.Log.Error(err)
+		return
 	}
 
 	// Output query as table
@@ -250,97 +387,213 @@ func updateQuestions(w *cli.Worker, so *internal.SlackOverflow) {
 		updateQuestions.DrawQuery(w, questionIds)
 	}
 
-	fetchQuestions := true
-	for fetchQuestions {
-		w.Log.Debugf("Fetching page %d", updateQuestions.GetCurrentPageNr())
-		if results, err := updateQuestions.Get(questionIds); results {
-			// Questions received
-			for _, q := range updateQuestions.Result.Items {
-
-				w.Log.Infof("Question: %s", q.Title)
-				w.Log.Infof("Url:      %s", q.ShareLink)
-				if debbuging {
-					printQuestion(q)
-				}
+	// Batches are fetched concurrently but synced one by one
+	complete := true
+	received := make(map[string]bool)
+	for batch := range so.QuestionBatches(questionIds, so.Config.StackExchange.ConcurrentRequests) {
+		if batch.Err != nil {
+			logStackExchangeError(w, so, batch.Err)
+		}
+		if !batch.Complete {
+			complete = false
+		}
+		// Questions received
+		for _, q := range batch.Items {
 
-				so.SyncQuestion(w, q)
-			}
-			if err != nil {
-				fetchQuestions = false
-				w.Log.Error(err.Error())
+			w.Log.Infof("Question: %s", q.Title)
+			w.Log.Infof("Url:      %s", q.ShareLink)
+			if debbuging {
+				printQuestion(q)
 			}
+
+			so.SyncQuestion(w, q)
+			received[strconv.Itoa(q.QID)] = true
 		}
+	}
 
-		// Done go to next page
-		if updateQuestions.HasMore() {
-			updateQuestions.NextPage()
+	// Questions missing from complete response have been deleted
+	if !complete || questionIdsCount == 0 {
+		return
+	}
+	for _, id := range strings.Split(questionIds, ";") {
+		if received[id] {
+			continue
+		}
+		QID, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+		so.DryRun.Add("mark deleted", "", fmt.Sprintf("question %d", QID))
+		msg, err := so.DB.StackExchangeQuestionDeleted(QID)
+		if err != nil {
+			w.Log.Error(err)
 		} else {
-			fetchQuestions = false
-			w.Log.Debug("There are no more questions to update.")
+			w.Log.Ok(msg)
 		}
 	}
 }
 
-func startWatching(w *cli.Worker, so *internal.SlackOverflow) {
-	// Check for New Questions from Stack Exchange
-	searchAdvanced := so.StackExchange.SearchAdvanced()
-
-	// Set it here so that it is allowed to override by config
-	searchAdvanced.Parameters.Set("site", so.Config.StackExchange.Site)
-
-	// Set all parameters from config
-	for param, value := range so.Config.StackExchange.SearchAdvanced {
-		searchAdvanced.Parameters.Set(param, value)
-	}
-	searchAdvanced.Parameters.Set("tagged", "php")
-	searchAdvanced.Parameters.Set("fromdate", fromDate.Unix()+1)
-
-	fetchQuestions := true
-	for fetchQuestions {
-		w.Log.Debugf("Fetching page %d", searchAdvanced.GetCurrentPageNr())
-		if results, err := searchAdvanced.Get(); results {
-			// Questions received
-			for _, q := range searchAdvanced.Result.Items {
-				fromDate = time.Unix(q.CreationDate, 0).UTC()
-				w.Log.Linef("Question: %s", q.Title)
-				w.Log.Linef("Url:      %s", q.ShareLink)
-				newq := internal.NewTable("Question ID", "Time", "Answers", "Comments", "Score", "Views", "Username")
-				newq.AddRow(
-					q.QID,
-					time.Unix(q.CreationDate, 0).Local().Format("15:04:05 Mon Jan _2 2006"),
-					q.AnswerCount,
-					q.CommentCount,
-					q.Score,
-					q.ViewCount,
-					q.Owner.DisplayName,
-				)
-				newq.Print()
-			}
-			if err != nil {
-				fetchQuestions = false
-				w.Log.Error(err.Error())
+// updateAnswers fetches answers of tracked questions so that answers from
+// linked Slack users can be credited
+func updateAnswers(w *cli.Worker, so *internal.SlackOverflow) {
+	links, err := so.DB.SlackUserLinkGetAll()
+	if err != nil {
+		w.Log.Error(err)
+		return
+	}
+	if len(links) == 0 {
+		w.Log.Debug("Stack Exchange: no linked Slack users, skipping answers.")
+		return
+	}
+	w.Log.Info("Stack Exchange: Updating answers of tracked questions.")
+
+	questionIds, questionIdsCount := so.DB.StackExchangeQuestionTrackedIds(
+		so.Config.StackExchange.QuestionsToWatch)
+	if questionIdsCount == 0 {
+		return
+	}
+
+	answers := so.StackExchange.AnswersOnQuestions()
+	if err = answers.Parameters.Set("site", so.Config.StackExchange.Site); err != nil {
+		w.Log.Error(err)
+		return
+	}
+
+	for _, batch := range internal.BatchIDs(questionIds, internal.MaxIDsPerRequest) {
+		pager := so.Pager(answers.Pages(batch))
+		for pager.Next() {
+			for _, a := range answers.Result.Items {
+				msg, err := so.DB.SyncStackExchangeAnswer(a)
+				if err != nil {
+					w.Log.Error(err)
+				} else {
+					w.Log.Debug(msg)
+				}
 			}
+		}
+		logPager(w, so, pager)
+	}
+}
 
-			if len(searchAdvanced.Result.Items) > 0 {
-				w.Log.Ok("Waiting for new questions!")
+func startWatching(w *cli.Worker, so *internal.SlackOverflow, opts watchOptions) {
+	// Check for New Questions from Stack Exchange
+	searchAdvanced, err := so.SearchAdvanced()
+	if err != nil {
+		w.Log.Error(err)
+		return
+	}
+	if opts.tagged != "" {
+		if err = searchAdvanced.Parameters.Set("tagged", opts.tagged); err != nil {
+			w.Log.Error(err)
+			return
+		}
+	}
+	if opts.site != "" {
+		if err = searchAdvanced.Parameters.Set("site", opts.site); err != nil {
+			w.Log.Error(err)
+			return
+		}
+	}
+	_ = searchAdvanced.Parameters.Set("fromdate", fromDate.Unix()+1)
+
+	shown := 0
+	pager := so.Pager(searchAdvanced.Pages())
+	for pager.Next() {
+		// Questions received
+		for _, q := range searchAdvanced.Result.Items {
+			if created := time.Unix(q.CreationDate, 0).UTC(); created.After(fromDate) {
+				fromDate = created
+			}
+			if opts.hasMin && q.Score < opts.minScore {
+				continue
 			}
+			printWatchedQuestion(w, q, opts.format)
+			shown++
 		}
+	}
+	logPager(w, so, pager)
+	if shown > 0 {
+		w.Log.Ok("Waiting for new questions!")
+	}
+	w.Log.Info(quotaGauge(so.StackExchange.GetQuotaRemaining(), so.StackExchange.GetQuotaMax()))
+}
 
-		// Done go to next page
-		if searchAdvanced.HasMore() || searchAdvanced.GetCurrentPageNr() > 10 {
-			searchAdvanced.NextPage()
-		} else {
-			fetchQuestions = false
+// printWatchedQuestion in given format
+func printWatchedQuestion(w *cli.Worker, q internal.QuestionObj, format string) {
+	created := time.Unix(q.CreationDate, 0).Local()
+	switch format {
+	case watchFormatJSON:
+		data, err := json.Marshal(q)
+		if err != nil {
+			w.Log.Error(err)
+			return
 		}
+		w.Log.Line(string(data))
+	case watchFormatLine:
+		w.Log.Linef("%s [%d] %s %s (%s)",
+			created.Format("2006-01-02 15:04"),
+			q.Score,
+			html.UnescapeString(q.Title),
+			q.ShareLink,
+			strings.Join(q.Tags, ", "),
+		)
+	default:
+		w.Log.Linef("Question: %s", html.UnescapeString(q.Title))
+		w.Log.Linef("Url:      %s", q.ShareLink)
+		newq := internal.NewTable("Question ID", "Time", "Answers", "Comments", "Score", "Views", "Username")
+		newq.AddRow(
+			q.QID,
+			created.Format("15:04:05 Mon Jan _2 2006"),
+			q.AnswerCount,
+			q.CommentCount,
+			q.Score,
+			q.ViewCount,
+			q.Owner.DisplayName,
+		)
+		newq.Print()
 	}
+}
 
-	w.Log.Infof(
-		"Stack Exchange Quota usage (%d/%d)",
-		so.StackExchange.GetQuotaRemaining(),
-		so.StackExchange.GetQuotaMax(),
+// quotaGauge renders remaining quota as bar e.g. Quota [#######-----] 70% (7000/10000)
+func quotaGauge(remaining int, max int) string {
+	if max <= 0 {
+		return "Quota [" + strings.Repeat("?", quotaGaugeWidth) + "] unknown"
+	}
+	filled := remaining * quotaGaugeWidth / max
+	if filled > quotaGaugeWidth {
+		filled = quotaGaugeWidth
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	return fmt.Sprintf("Quota [%s%s] %d%% (%d/%d)",
+		strings.Repeat("#", filled),
+		strings.Repeat("-", quotaGaugeWidth-filled),
+		remaining*100/max,
+		remaining,
+		max,
 	)
 }
 
+// logPager logs why paging stopped
+func logPager(w *cli.Worker, so *internal.SlackOverflow, pager *internal.Pager) {
+	if err := pager.Err(); err != nil {
+		logStackExchangeError(w, so, err)
+		return
+	}
+	w.Log.Debugf("Stack Exchange: %d items from %d page(s), %s.", pager.Items(), pager.Page(), pager.StopReason())
+}
+
+// logStackExchangeError logs error and asks to login again if access token
+// is not valid anymore, rest of the requests are made without the token
+func logStackExchangeError(w *cli.Worker, so *internal.SlackOverflow, err error) {
+	w.Log.Error(err)
+	if internal.IsAccessTokenError(err) {
+		w.Log.Warning(internal.ErrAccessTokenExpired)
+		so.StackExchange.SetAccessToken("")
+	}
+}
+
 func printQuestion(q internal.QuestionObj) {
 	newq := internal.NewTable("Question ID", "Time", "Answers", "Comments", "Score", "Views", "Username")
 	newq.AddRow(
@@ -0,0 +1,125 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal/fakese"
+)
+
+func TestGetNewQuestionsRetriesFailedRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		fail func(se *fakese.Server)
+		// retried by HTTP client during the same run
+		retried bool
+	}{
+		{"api error", func(se *fakese.Server) {
+			se.FailNext(502, "throttle_violation", "too many requests from this IP")
+		}, false},
+		{"proxy error", func(se *fakese.Server) {
+			se.FailNextHTTP(403, "<html><body>Forbidden</body></html>")
+		}, false},
+		{"server error", func(se *fakese.Server) {
+			se.FailNextHTTP(503, "<html><body>Service Unavailable</body></html>")
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, fakese.Options{Seed: 1})
+			defer env.Close()
+			feed := newQuestionsFeed(env.so)
+
+			// Latest question is stored on first run
+			getNewQuestions(env.w, env.so)
+			cursor := env.so.DB.FindStackExchangeSyncCursor(feed)
+			if cursor.Feed == "" {
+				t.Fatal("sync cursor was not stored")
+			}
+
+			q := env.se.AddQuestion()
+			tt.fail(env.se)
+			getNewQuestions(env.w, env.so)
+			if !tt.retried {
+				if stored := env.so.DB.FindStackExchangeQuestion(q.QID); stored.QID != 0 {
+					t.Errorf("question %d stored from failed request", q.QID)
+				}
+				if got := env.so.DB.FindStackExchangeSyncCursor(feed); !got.LastCreationDate.Equal(cursor.LastCreationDate) {
+					t.Errorf("cursor advanced to %s after failed request, want %s", got.LastCreationDate, cursor.LastCreationDate)
+				}
+				getNewQuestions(env.w, env.so)
+			}
+			if stored := env.so.DB.FindStackExchangeQuestion(q.QID); stored.QID != q.QID {
+				t.Errorf("question %d not stored on retry", q.QID)
+			}
+			if got := env.so.DB.FindStackExchangeSyncCursor(feed); got.LastCreationDate.Unix() != q.CreationDate {
+				t.Errorf("cursor at %s, want creation date of question %d", got.LastCreationDate, q.QID)
+			}
+		})
+	}
+}
+
+func TestGetNewQuestionsHonoursBackoff(t *testing.T) {
+	env := newTestEnv(t, fakese.Options{Seed: 1})
+	defer env.Close()
+
+	env.se.BackoffNext(1)
+	getNewQuestions(env.w, env.so)
+	requests := len(env.se.Requests())
+	start := time.Now()
+	updateQuestions(env.w, env.so)
+	if len(env.se.Requests()) == requests {
+		t.Fatal("questions were not updated")
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("next request made after %s, want backoff of 1s", elapsed)
+	}
+}
+
+func TestUpdateQuestionsRecordsEdits(t *testing.T) {
+	env := newTestEnv(t, fakese.Options{Seed: 1})
+	defer env.Close()
+
+	q := env.se.AddQuestion()
+	if !env.so.SyncQuestion(env.w, q) {
+		t.Fatalf("question %d was not stored", q.QID)
+	}
+	env.se.EditQuestion(q.QID, func(q *internal.QuestionObj) {
+		q.Title = "How to test edits?"
+		q.Tags = []string{"aframe", "testing"}
+	})
+	updateQuestions(env.w, env.so)
+
+	edits, err := env.so.DB.StackExchangeQuestionEditsNotPosted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 1 {
+		t.Fatalf("%d edits recorded, want 1", len(edits))
+	}
+	edit := edits[0]
+	if edit.QID != q.QID || edit.OldTitle != q.Title || edit.NewTitle != "How to test edits?" {
+		t.Errorf("edit of question %d title %q → %q, want %d %q → %q",
+			edit.QID, edit.OldTitle, edit.NewTitle, q.QID, q.Title, "How to test edits?")
+	}
+	if len(edit.TagsAdded) != 1 || edit.TagsAdded[0] != "testing" {
+		t.Errorf("tags added %v, want [testing]", edit.TagsAdded)
+	}
+	if edit.Editor == "" {
+		t.Error("editor of question was not resolved from revisions")
+	}
+	if stored := env.so.DB.FindStackExchangeQuestion(q.QID); stored.Title != "How to test edits?" {
+		t.Errorf("stored title %q, want edited title", stored.Title)
+	}
+
+	// Same state again is not an edit
+	updateQuestions(env.w, env.so)
+	if edits, _ := env.so.DB.StackExchangeQuestionEditsNotPosted(); len(edits) != 1 {
+		t.Errorf("%d edits recorded after unchanged update, want 1", len(edits))
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/howi-ce/howi/std/errors"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/nlopes/slack"
+)
+
+func init() {
+	slashSubcommands["subscribe"] = slashSubcommand{"tag|keyword|question <value>", slashSubscribe}
+	slashSubcommands["unsubscribe"] = slashSubcommand{"tag|keyword|question <value>", slashUnsubscribe}
+	slashSubcommands["subscriptions"] = slashSubcommand{"", slashSubscriptions}
+}
+
+// SlackSubscribe returns command managing direct message subscriptions
+func SlackSubscribe(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("subscribe")
+	scmd.SetShortDesc("Subscribe Slack user to direct messages about tags, title keywords or questions.")
+
+	userFlag := flags.NewStringFlag("user")
+	userFlag.SetUsage("Slack user ID e.g. U024BE7LH")
+	scmd.AddFlag(userFlag)
+
+	tagFlag := flags.NewStringFlag("tag")
+	tagFlag.SetUsage("notify about new questions with given tag")
+	scmd.AddFlag(tagFlag)
+
+	keywordFlag := flags.NewStringFlag("keyword")
+	keywordFlag.SetUsage("notify about new questions with given keyword in title")
+	scmd.AddFlag(keywordFlag)
+
+	questionFlag := flags.NewStringFlag("question")
+	questionFlag.SetUsage("notify about new answers, accepted answer and closing of given question ID")
+	scmd.AddFlag(questionFlag)
+
+	rmFlag := flags.NewBoolFlag("remove")
+	rmFlag.SetUsage("Remove given subscription instead")
+	scmd.AddFlag(rmFlag)
+
+	lsFlag := flags.NewBoolFlag("list")
+	lsFlag.SetUsage("List subscriptions, of given --user only if set")
+	scmd.AddFlag(lsFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		user, _ := w.Flag("user")
+		remove, _ := w.Flag("remove")
+		list, _ := w.Flag("list")
+		slackUID := ""
+		if user.Present() {
+			slackUID = user.Value().String()
+		}
+
+		if list.Present() {
+			subs, err := so.DB.SlackSubscriptionGetAll(slackUID)
+			if err != nil {
+				w.Fail(err.Error())
+				return
+			}
+			table := internal.NewTable("Slack user", "Kind", "Value", "Created")
+			for _, sub := range subs {
+				table.AddRow(sub.SlackUID, sub.Kind, sub.Value, sub.Created.Format("2006-01-02"))
+			}
+			table.Print()
+			return
+		}
+
+		if slackUID == "" {
+			w.Fail("--user must be provided")
+			return
+		}
+		var kind, value string
+		for _, k := range []string{internal.SubscriptionTag, internal.SubscriptionKeyword, internal.SubscriptionQuestion} {
+			if f, _ := w.Flag(k); f.Present() {
+				kind, value = k, f.Value().String()
+			}
+		}
+		if kind == "" {
+			w.Fail("one of --tag, --keyword or --question must be provided")
+			return
+		}
+
+		if remove.Present() {
+			msg, err := unsubscribe(so, slackUID, kind, value)
+			if err != nil {
+				w.Fail(err.Error())
+				return
+			}
+			w.Log.Ok(msg)
+			return
+		}
+		msg, err := subscribe(so, slackUID, kind, value)
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		w.Log.Ok(msg)
+	})
+	return scmd
+}
+
+// subscribe validates and stores subscription
+func subscribe(so *internal.SlackOverflow, slackUID string, kind string, value string) (string, error) {
+	sub := internal.SlackSubscription{SlackUID: slackUID, Kind: kind}
+	switch kind {
+	case internal.SubscriptionTag, internal.SubscriptionKeyword:
+		sub.Value = strings.ToLower(strings.TrimSpace(value))
+	case internal.SubscriptionQuestion:
+		QID, err := strconv.Atoi(value)
+		if err != nil || QID <= 0 {
+			return "", errors.Newf("invalid question id %q", value)
+		}
+		sub.Value = strconv.Itoa(QID)
+		// Notify only about changes from now on
+		question := so.DB.FindStackExchangeQuestion(QID)
+		sub.AnswerCount = question.AnswerCount
+		sub.IsAnswered = question.IsAnswered
+		sub.ClosedReason = question.ClosedReason
+		sub.AcceptedAnswerID = question.AcceptedAnswerID
+		sub.Lifecycle = question.Lifecycle
+		if sub.Lifecycle == "" {
+			sub.Lifecycle = internal.LifecycleOpen
+		}
+	default:
+		return "", errors.Newf("unknown subscription %q expected tag, keyword or question", kind)
+	}
+	if sub.Value == "" {
+		return "", errors.Newf("%s can not be empty", kind)
+	}
+	return so.DB.SlackSubscriptionCreate(sub)
+}
+
+func unsubscribe(so *internal.SlackOverflow, slackUID string, kind string, value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	deleted, err := so.DB.SlackSubscriptionDelete(slackUID, kind, value)
+	if err != nil {
+		return "", err
+	}
+	if !deleted {
+		return "", errors.Newf("%s is not subscribed to %s %s", slackUID, kind, value)
+	}
+	return fmt.Sprintf("%s unsubscribed from %s %s.", slackUID, kind, value), nil
+}
+
+func slashSubscribe(w *cli.Worker, so *internal.SlackOverflow, cmd internal.SlackCommand, args []string) (*internal.SlackResponse, error) {
+	if len(args) < 2 {
+		return &internal.SlackResponse{
+			ResponseType: "ephemeral",
+			Text:         "Usage: `" + slashCommand + " subscribe tag|keyword|question <value>`",
+		}, nil
+	}
+	msg, err := subscribe(so, cmd.UserID, args[0], strings.Join(args[1:], " "))
+	if err != nil {
+		return nil, err
+	}
+	w.Log.Ok(msg)
+	return &internal.SlackResponse{
+		ResponseType: "ephemeral",
+		Text:         fmt.Sprintf(":bell: You will receive direct message about %s %s.", args[0], strings.Join(args[1:], " ")),
+	}, nil
+}
+
+func slashUnsubscribe(w *cli.Worker, so *internal.SlackOverflow, cmd internal.SlackCommand, args []string) (*internal.SlackResponse, error) {
+	if len(args) < 2 {
+		return &internal.SlackResponse{
+			ResponseType: "ephemeral",
+			Text:         "Usage: `" + slashCommand + " unsubscribe tag|keyword|question <value>`",
+		}, nil
+	}
+	msg, err := unsubscribe(so, cmd.UserID, args[0], strings.Join(args[1:], " "))
+	if err != nil {
+		return nil, err
+	}
+	w.Log.Ok(msg)
+	return &internal.SlackResponse{ResponseType: "ephemeral", Text: ":no_bell: Unsubscribed."}, nil
+}
+
+func slashSubscriptions(w *cli.Worker, so *internal.SlackOverflow, cmd internal.SlackCommand, args []string) (*internal.SlackResponse, error) {
+	subs, err := so.DB.SlackSubscriptionGetAll(cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(subs) == 0 {
+		return &internal.SlackResponse{ResponseType: "ephemeral", Text: "You have no subscriptions."}, nil
+	}
+	var lines []string
+	for _, sub := range subs {
+		lines = append(lines, fmt.Sprintf("• %s `%s`", sub.Kind, sub.Value))
+	}
+	return &internal.SlackResponse{ResponseType: "ephemeral", Text: strings.Join(lines, "\n")}, nil
+}
+
+// subscriptionMatches returns true if new question matches tag or keyword subscription
+func subscriptionMatches(sub internal.SlackSubscription, question internal.StackExchangeQuestion) bool {
+	switch sub.Kind {
+	case internal.SubscriptionTag:
+		for _, tag := range strings.Split(question.Tags, ";") {
+			if strings.ToLower(tag) == sub.Value {
+				return true
+			}
+		}
+	case internal.SubscriptionKeyword:
+		return strings.Contains(strings.ToLower(question.Title), sub.Value)
+	}
+	return false
+}
+
+// slackNotifyNewQuestion sends direct message to users subscribed to tags or
+// keywords of posted question, each user is notified once per question
+func slackNotifyNewQuestion(w *cli.Worker, so *internal.SlackOverflow, question internal.StackExchangeQuestion) {
+	var subs []internal.SlackSubscription
+	for _, kind := range []string{internal.SubscriptionTag, internal.SubscriptionKeyword} {
+		kindSubs, err := so.DB.SlackSubscriptionsByKind(kind)
+		if err != nil {
+			w.Log.Error(err)
+			return
+		}
+		subs = append(subs, kindSubs...)
+	}
+	notified := make(map[string]bool)
+	for _, sub := range subs {
+		if notified[sub.SlackUID] || !subscriptionMatches(sub, question) {
+			continue
+		}
+		notified[sub.SlackUID] = true
+		text := fmt.Sprintf(":bell: New question matching your %s subscription `%s`", sub.Kind, sub.Value)
+		if err := slackDirectMessage(so, sub.SlackUID, text, question); err != nil {
+			w.Log.Errorf("Slack: direct message to %s: %s", sub.SlackUID, err.Error())
+		}
+	}
+}
+
+// slackNotifyQuestionChanges sends direct message to users subscribed to
+// question when it gets new answer, accepted answer, gets closed, deleted
+// or migrated
+func slackNotifyQuestionChanges(w *cli.Worker, so *internal.SlackOverflow, subs []internal.SlackSubscription, question internal.StackExchangeQuestion) {
+	qid := strconv.Itoa(question.QID)
+	for _, sub := range subs {
+		if sub.Value != qid {
+			continue
+		}
+		var changes []string
+		if question.AnswerCount > sub.AnswerCount {
+			changes = append(changes, fmt.Sprintf(":pencil: %d new answer(s)", question.AnswerCount-sub.AnswerCount))
+		}
+		// is_answered is set for any upvoted answer, only acceptance is reported
+		if question.AcceptedAnswerID > 0 && question.AcceptedAnswerID != sub.AcceptedAnswerID {
+			changes = append(changes, ":white_check_mark: answer was accepted")
+		}
+		if question.ClosedReason != "" && sub.ClosedReason == "" {
+			changes = append(changes, fmt.Sprintf(":lock: question was closed as %s", question.ClosedReason))
+		}
+		if question.Lifecycle != sub.Lifecycle {
+			switch question.Lifecycle {
+			case internal.LifecycleDeleted:
+				changes = append(changes, ":wastebasket: question was deleted")
+			case internal.LifecycleMigrated:
+				changes = append(changes, fmt.Sprintf(":truck: question was migrated to %s", question.MigratedTo))
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		text := "Update on question you follow: " + strings.Join(changes, ", ")
+		if err := slackDirectMessage(so, sub.SlackUID, text, question); err != nil {
+			w.Log.Errorf("Slack: direct message to %s: %s", sub.SlackUID, err.Error())
+			continue
+		}
+		sub.AnswerCount = question.AnswerCount
+		sub.IsAnswered = question.IsAnswered
+		sub.ClosedReason = question.ClosedReason
+		sub.AcceptedAnswerID = question.AcceptedAnswerID
+		sub.Lifecycle = question.Lifecycle
+		if err := so.DB.SlackSubscriptionUpdateState(sub); err != nil {
+			w.Log.Error(err)
+		}
+	}
+}
+
+// slackDirectMessage sends question to Slack user
+func slackDirectMessage(so *internal.SlackOverflow, slackUID string, text string, question internal.StackExchangeQuestion) error {
+	api := so.SlackClient()
+	_, _, channelID, err := api.OpenIMChannel(slackUID)
+	if err != nil {
+		return err
+	}
+	params := slack.NewPostMessageParameters()
+	params.AsUser = false
+	params.Attachments = []slack.Attachment{{
+		Fallback:  question.Title,
+		Title:     question.Title,
+		TitleLink: question.ShareLink,
+		Text:      strings.Replace(question.Tags, ";", " ", -1),
+	}}
+	_, _, err = api.PostMessage(channelID, text, params)
+	return err
+}
@@ -0,0 +1,317 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+)
+
+// maxTagsPerRequest is maximum number of {tags} accepted by tags endpoints
+const maxTagsPerRequest = 20
+
+// StackExchangeTags returns Stack Exchange tags command
+func StackExchangeTags(so *internal.SlackOverflow) cli.Command {
+	cmd := cli.NewCommand("tags")
+	cmd.SetShortDesc("Inspect tracked tags, their synonyms and related tags see slackoverflow stackexchange tags --help for more info.")
+	cmd.AddSubcommand(StackExchangeTagsInfo(so))
+	cmd.AddSubcommand(StackExchangeTagsSynonyms(so))
+	cmd.AddSubcommand(StackExchangeTagsRelated(so))
+	return cmd
+}
+
+// StackExchangeTagsInfo returns command showing tag info
+func StackExchangeTagsInfo(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("info")
+	scmd.SetShortDesc("Show question count and activity of tags.")
+
+	tagFlag := flags.NewStringFlag("tag")
+	tagFlag.SetUsage("semicolon delimited tags (default: tracked tags from search-advanced.tagged)")
+	scmd.AddFlag(tagFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		tags, ok := tagsSession(w, so)
+		if !ok {
+			return
+		}
+		info, err := getTagsInfo(so, tags)
+		if err != nil {
+			logStackExchangeError(w, so, err)
+			return
+		}
+		table := internal.NewTable("Tag", "Questions", "Has synonyms", "Moderator only", "Required", "Last activity")
+		for _, tag := range tags {
+			t, exists := info[strings.ToLower(tag)]
+			if !exists {
+				table.AddRow(tag, "not found", "", "", "", "")
+				continue
+			}
+			table.AddRow(t.Name, t.Count, t.HasSynonyms, t.IsModeratorOnly, t.IsRequired, tagDate(t.LastActivityDate))
+		}
+		table.Print()
+	})
+	return scmd
+}
+
+// StackExchangeTagsSynonyms returns command listing synonyms of tags
+func StackExchangeTagsSynonyms(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("synonyms")
+	scmd.SetShortDesc("List synonyms pointing to tags, questions tagged with synonym are retagged to master tag.")
+
+	tagFlag := flags.NewStringFlag("tag")
+	tagFlag.SetUsage("semicolon delimited tags (default: tracked tags from search-advanced.tagged)")
+	scmd.AddFlag(tagFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		tags, ok := tagsSession(w, so)
+		if !ok {
+			return
+		}
+		synonyms, err := getTagSynonyms(so, tags)
+		if err != nil {
+			logStackExchangeError(w, so, err)
+			return
+		}
+		table := internal.NewTable("Synonym", "Master tag", "Applied", "Last applied", "Created")
+		for _, s := range synonyms {
+			table.AddRow(s.FromTag, s.ToTag, s.AppliedCount, tagDate(s.LastAppliedDate), tagDate(s.CreationDate))
+		}
+		table.Print()
+	})
+	return scmd
+}
+
+// StackExchangeTagsRelated returns command suggesting related tags
+func StackExchangeTagsRelated(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("related")
+	scmd.SetShortDesc("Suggest related tags which are not tracked yet.")
+
+	tagFlag := flags.NewStringFlag("tag")
+	tagFlag.SetUsage("semicolon delimited tags (default: tracked tags from search-advanced.tagged)")
+	scmd.AddFlag(tagFlag)
+
+	minFlag := flags.NewStringFlag("min-count")
+	minFlag.SetUsage("minimum number of questions of suggested tag (default: 0)")
+	scmd.AddFlag(minFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		tags, ok := tagsSession(w, so)
+		if !ok {
+			return
+		}
+		minCount := 0
+		if f, _ := w.Flag("min-count"); f.Present() {
+			var err error
+			if minCount, err = strconv.Atoi(f.Value().String()); err != nil {
+				w.Fail("--min-count must be a number")
+				return
+			}
+		}
+		tracked := make(map[string]bool)
+		for _, tag := range so.TrackedTags() {
+			tracked[strings.ToLower(tag)] = true
+		}
+		for _, tag := range tags {
+			tracked[strings.ToLower(tag)] = true
+		}
+
+		related := make(map[string]internal.TagObj)
+		relatedTo := make(map[string][]string)
+		for _, tag := range tags {
+			items, err := getRelatedTags(so, tag)
+			if err != nil {
+				logStackExchangeError(w, so, err)
+				return
+			}
+			for _, t := range items {
+				if tracked[strings.ToLower(t.Name)] || t.Count < minCount {
+					continue
+				}
+				related[t.Name] = t
+				relatedTo[t.Name] = append(relatedTo[t.Name], tag)
+			}
+		}
+		var names []string
+		for name := range related {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			return related[names[i]].Count > related[names[j]].Count
+		})
+		table := internal.NewTable("Tag", "Questions", "Related to")
+		for _, name := range names {
+			table.AddRow(name, related[name].Count, strings.Join(relatedTo[name], ", "))
+		}
+		table.Print()
+	})
+	return scmd
+}
+
+// tagsSession starts session and returns tags of --tag flag or tracked tags
+func tagsSession(w *cli.Worker, so *internal.SlackOverflow) ([]string, bool) {
+	if err := so.Session(w); err != nil {
+		w.Fail(err.Error())
+		return nil, false
+	}
+	tags := so.TrackedTags()
+	if f, _ := w.Flag("tag"); f.Present() {
+		tags = nil
+		for _, tag := range strings.Split(f.Value().String(), ";") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	if len(tags) == 0 {
+		w.Fail("--tag must be provided, no tags are tracked")
+		return nil, false
+	}
+	return tags, true
+}
+
+// getTagsInfo returns existing tags by lower case name
+func getTagsInfo(so *internal.SlackOverflow, tags []string) (map[string]internal.TagObj, error) {
+	info := make(map[string]internal.TagObj)
+	for _, batch := range internal.BatchIDs(strings.Join(tags, ";"), maxTagsPerRequest) {
+		tagsInfo, err := so.TagsInfo()
+		if err != nil {
+			return info, err
+		}
+		pager := so.Pager(tagsInfo.Pages(batch))
+		for pager.Next() {
+			for _, t := range tagsInfo.Result.Items {
+				info[strings.ToLower(t.Name)] = t
+			}
+		}
+		if err := pager.Err(); err != nil {
+			return info, err
+		}
+	}
+	return info, nil
+}
+
+// getTagSynonyms returns synonyms pointing to given tags
+func getTagSynonyms(so *internal.SlackOverflow, tags []string) ([]internal.TagSynonymObj, error) {
+	var synonyms []internal.TagSynonymObj
+	for _, batch := range internal.BatchIDs(strings.Join(tags, ";"), maxTagsPerRequest) {
+		tagsSynonyms, err := so.TagsSynonyms()
+		if err != nil {
+			return synonyms, err
+		}
+		pager := so.Pager(tagsSynonyms.Pages(batch))
+		for pager.Next() {
+			synonyms = append(synonyms, tagsSynonyms.Result.Items...)
+		}
+		if err := pager.Err(); err != nil {
+			return synonyms, err
+		}
+	}
+	return synonyms, nil
+}
+
+// getSynonymsOf returns synonym records of given tags by lower case synonym
+// name. API can not look up synonyms by from_tag, so synonyms of the site
+// are scanned most applied first until all tags are found. If paging stopped
+// before all synonyms of the site were scanned, number of scanned synonyms
+// is returned, 0 otherwise.
+func getSynonymsOf(so *internal.SlackOverflow, tags []string) (map[string]internal.TagSynonymObj, int, error) {
+	found := make(map[string]internal.TagSynonymObj)
+	wanted := make(map[string]bool)
+	for _, tag := range tags {
+		wanted[strings.ToLower(tag)] = true
+	}
+	tagsSynonyms, err := so.TagsSynonyms()
+	if err != nil {
+		return found, 0, err
+	}
+	pager := so.Pager(tagsSynonyms.Pages(""))
+	for len(found) < len(wanted) && pager.Next() {
+		for _, s := range tagsSynonyms.Result.Items {
+			if from := strings.ToLower(s.FromTag); wanted[from] {
+				found[from] = s
+			}
+		}
+	}
+	if len(found) < len(wanted) && !pager.Complete() {
+		return found, pager.Items(), pager.Err()
+	}
+	return found, 0, pager.Err()
+}
+
+// getRelatedTags returns first page of tags related to tag
+func getRelatedTags(so *internal.SlackOverflow, tag string) ([]internal.TagObj, error) {
+	related, err := so.TagsRelated()
+	if err != nil {
+		return nil, err
+	}
+	pager := so.Pager(related.Pages(tag))
+	pager.MaxPages = 1
+	var items []internal.TagObj
+	for pager.Next() {
+		items = append(items, related.Result.Items...)
+	}
+	return items, pager.Err()
+}
+
+// checkTrackedTags returns warnings about tracked tags which do not exist on
+// configured site or are synonyms of other tag
+func checkTrackedTags(so *internal.SlackOverflow) ([]string, error) {
+	tags := so.TrackedTags()
+	if len(tags) == 0 {
+		return []string{"stackexchange.search-advanced.tagged is empty, questions of all tags are tracked"}, nil
+	}
+	info, err := getTagsInfo(so, tags)
+	if err != nil {
+		return nil, err
+	}
+	// Synonyms are info of their tag, so only missing tags can be synonyms
+	var missing []string
+	tracked := make(map[string]bool)
+	for _, tag := range tags {
+		tracked[strings.ToLower(tag)] = true
+		if _, exists := info[strings.ToLower(tag)]; !exists {
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+	synonyms, checked, err := getSynonymsOf(so, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for _, tag := range missing {
+		if s, ok := synonyms[strings.ToLower(tag)]; ok {
+			if tracked[strings.ToLower(s.ToTag)] {
+				warnings = append(warnings, fmt.Sprintf("tag %s is a synonym of %s which is already tracked, remove %s from stackexchange.search-advanced.tagged", tag, s.ToTag, tag))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("tag %s is a synonym of %s, replace %s with %s in stackexchange.search-advanced.tagged", tag, s.ToTag, tag, s.ToTag))
+			}
+			continue
+		}
+		if checked > 0 {
+			warnings = append(warnings, fmt.Sprintf("tag %s not found on %s (checked first %d synonyms)", tag, so.Config.StackExchange.Site, checked))
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("tag %s does not exist on %s", tag, so.Config.StackExchange.Site))
+	}
+	return warnings, nil
+}
+
+func tagDate(date int64) string {
+	if date == 0 {
+		return ""
+	}
+	return time.Unix(date, 0).UTC().Format("2006-01-02")
+}
@@ -0,0 +1,98 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// tagsAPI serves tags/{tags}/info of existing tags and endless pages of
+// tags/synonyms with a-frame → aframe on first page
+type tagsAPI struct {
+	mu       sync.Mutex
+	existing []string
+	synonyms int
+}
+
+func (api *tagsAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 4 && parts[3] == "info":
+		var items []string
+		for _, tag := range strings.Split(parts[2], ";") {
+			for _, existing := range api.existing {
+				if tag == existing {
+					items = append(items, fmt.Sprintf(`{"name":%q,"count":10}`, tag))
+				}
+			}
+		}
+		fmt.Fprintf(w, `{"items":[%s],"has_more":false,"quota_max":300,"quota_remaining":299}`, strings.Join(items, ","))
+	case len(parts) == 3 && parts[2] == "synonyms":
+		api.synonyms++
+		from := fmt.Sprintf("tag-%d", api.synonyms)
+		if api.synonyms == 1 {
+			from = "a-frame"
+		}
+		fmt.Fprintf(w, `{"items":[{"from_tag":%q,"to_tag":"aframe"},{"from_tag":"other-%d","to_tag":"other"}],"has_more":true,"quota_max":300,"quota_remaining":299}`, from, api.synonyms)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestCheckTrackedTags(t *testing.T) {
+	tests := []struct {
+		tagged   string
+		synonyms int
+		warnings []string
+	}{
+		{"aframe;three.js", 0, nil},
+		{"aframe;a-frame", 1, []string{
+			"tag a-frame is a synonym of aframe which is already tracked, remove a-frame from stackexchange.search-advanced.tagged",
+		}},
+		{"a-frame;rare", 2, []string{
+			"tag a-frame is a synonym of aframe, replace a-frame with aframe in stackexchange.search-advanced.tagged",
+			"tag rare not found on stackoverflow (checked first 4 synonyms)",
+		}},
+	}
+	dir, err := ioutil.TempDir("", "slackoverflow-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for i, tt := range tests {
+		t.Run(tt.tagged, func(t *testing.T) {
+			api := &tagsAPI{existing: []string{"aframe", "three.js"}}
+			server := httptest.NewServer(api)
+			defer server.Close()
+			so := newTestSlackOverflow(t, filepath.Join(dir, fmt.Sprintf("%d.db3", i)), server.URL, "")
+			defer so.DB.Close()
+			so.Config.StackExchange.SearchAdvanced["tagged"] = tt.tagged
+			so.Config.StackExchange.MaxPages = 2
+
+			warnings, err := checkTrackedTags(so)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("warnings\n%q\nwant\n%q", warnings, tt.warnings)
+			}
+			if api.synonyms != tt.synonyms {
+				t.Errorf("%d pages of synonyms requested, want %d", api.synonyms, tt.synonyms)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/std/errors"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+	"github.com/nlopes/slack"
+)
+
+const (
+	triageCallbackID = "question_triage"
+	triageSnooze     = 4 * time.Hour
+)
+
+// triageButtons in order they are shown under the question
+var triageButtons = []struct {
+	state string
+	text  string
+}{
+	{internal.TriageClaimed, "I'll take it"},
+	{internal.TriageSnoozed, "Snooze 4h"},
+	{internal.TriageHandled, "Handled"},
+	{internal.TriageIgnored, "Not relevant"},
+}
+
+// triageActions returns buttons for question, button of current state is highlighted
+func triageActions(QID int, triage internal.SlackQuestionTriage) []slack.AttachmentAction {
+	var actions []slack.AttachmentAction
+	for _, b := range triageButtons {
+		action := slack.AttachmentAction{
+			Name:  b.state,
+			Text:  b.text,
+			Type:  "button",
+			Value: strconv.Itoa(QID),
+		}
+		if triage.State == b.state {
+			action.Style = "primary"
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// triageStatus returns line describing triage state of question
+func triageStatus(triage internal.SlackQuestionTriage) string {
+	switch triage.State {
+	case internal.TriageClaimed:
+		return fmt.Sprintf(":raising_hand: <@%s> is on it", triage.User)
+	case internal.TriageSnoozed:
+		return fmt.Sprintf(":zzz: snoozed by <@%s> until %s",
+			triage.User, triage.SnoozeUntil.UTC().Format("15:04 Mon Jan _2 MST"))
+	case internal.TriageHandled:
+		return fmt.Sprintf(":white_check_mark: handled by <@%s>", triage.User)
+	case internal.TriageIgnored:
+		return fmt.Sprintf(":no_entry_sign: marked not relevant by <@%s>", triage.User)
+	}
+	return ""
+}
+
+// handleTriage updates triage state when one of the question buttons is
+// clicked and re-renders the message right away.
+func handleTriage(w *cli.Worker, so *internal.SlackOverflow) internal.SlackInteractionHandlerFunc {
+	return func(cb slack.AttachmentActionCallback) (*internal.SlackResponse, error) {
+		if len(cb.Actions) == 0 {
+			return nil, errors.New("triage: no action received")
+		}
+		action := cb.Actions[0]
+		QID, err := strconv.Atoi(action.Value)
+		if err != nil {
+			return nil, errors.Newf("triage: invalid question id %q", action.Value)
+		}
+		question := so.DB.FindStackExchangeQuestion(QID)
+		if question.QID == 0 {
+			return nil, errors.Newf("triage: question %d is not tracked anymore", QID)
+		}
+
+		triage := so.DB.FindSlackQuestionTriage(QID)
+		now := time.Now().UTC()
+		if triage.State == action.Name && triage.User == cb.User.ID {
+			// Clicking own state again reopens the question
+			triage.State = ""
+			triage.SnoozeUntil = time.Time{}
+		} else {
+			triage.State = action.Name
+			triage.User = cb.User.ID
+			triage.SnoozeUntil = time.Time{}
+			if action.Name == internal.TriageSnoozed {
+				triage.SnoozeUntil = now.Add(triageSnooze)
+			}
+		}
+		triage.QID = QID
+		triage.Updated = now
+		msg, err := so.DB.SlackQuestionTriageSave(triage)
+		if err != nil {
+			return nil, err
+		}
+		w.Log.Infof("Slack: %s by %s", msg, cb.User.Name)
+
+		channel := cb.Channel.ID
+		if channel == "" {
+			channel = so.Config.Slack.Channel
+		}
+		api := so.SlackClient()
+		if _, err := api.UpdateMessage(channel, cb.MessageTs, slackQuestionAttachment(so, question)); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
+// slackResurfaceSnoozed posts reminder to question thread when snooze expires
+func slackResurfaceSnoozed(w *cli.Worker, so *internal.SlackOverflow) {
+	w.Log.Info("Slack: Checking snoozed questions.")
+
+	expired, err := so.DB.SlackQuestionTriageSnoozeExpired(time.Now().UTC())
+	if err != nil {
+		w.Log.Error(err)
+		return
+	}
+	api := so.SlackClient()
+	for _, triage := range expired {
+		link := so.DB.FindSlackQuestion(triage.QID)
+		question := so.DB.FindStackExchangeQuestion(triage.QID)
+		if link.QID == 0 || question.QID == 0 {
+			so.DB.SlackQuestionTriageDelete(triage.QID)
+			continue
+		}
+
+		params := slack.NewPostMessageParameters()
+		params.AsUser = false
+		params.LinkNames = 1
+		params.ThreadTimestamp = link.TS
+		text := fmt.Sprintf(":alarm_clock: <@%s> snooze is over, this question is back.", triage.User)
+		if _, _, err := api.PostMessage(link.Channel, text, params); err != nil {
+			w.Log.Errorf("Slack channel (%s): %s", link.Channel, err.Error())
+			continue
+		}
+
+		triage.State = ""
+		triage.SnoozeUntil = time.Time{}
+		triage.Updated = time.Now().UTC()
+		if _, err := so.DB.SlackQuestionTriageSave(triage); err != nil {
+			w.Log.Error(err)
+			continue
+		}
+		if _, err := api.UpdateMessage(link.Channel, link.TS, slackQuestionAttachment(so, question)); err != nil {
+			w.Log.Errorf("Slack channel (%s): %s", link.Channel, err.Error())
+			continue
+		}
+		w.Log.Infof("Slack: snoozed question resurfaced: %s", question.Title)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/addon/application/plugin/cli/flags"
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+)
+
+// StackExchangeUsers returns Stack Exchange users command
+func StackExchangeUsers(so *internal.SlackOverflow) cli.Command {
+	cmd := cli.NewCommand("users")
+	cmd.SetShortDesc("Track Stack Exchange users and top answerers of tracked tags see slackoverflow stackexchange users --help for more info.")
+	cmd.AddSubcommand(StackExchangeUsersRefresh(so))
+	cmd.AddSubcommand(StackExchangeUsersTop(so))
+	cmd.AddSubcommand(StackExchangeUsersHistory(so))
+	return cmd
+}
+
+// StackExchangeUsersRefresh returns command refreshing known users and top answerers
+func StackExchangeUsersRefresh(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("refresh")
+	scmd.SetShortDesc("Refresh top answerers of tracked tags and all known users, record their reputation.")
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		refreshTopAnswerers(w, so)
+		refreshUsers(w, so)
+	})
+	return scmd
+}
+
+// StackExchangeUsersTop returns command listing top answerers of tag
+func StackExchangeUsersTop(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("top")
+	scmd.SetShortDesc("List top answerers of tag.")
+
+	tagFlag := flags.NewStringFlag("tag")
+	tagFlag.SetUsage("tag e.g. aframe (default: all tracked tags)")
+	scmd.AddFlag(tagFlag)
+
+	periodFlag := flags.NewStringFlag("period")
+	periodFlag.SetUsage("month or all_time (default: all_time)")
+	scmd.AddFlag(periodFlag)
+
+	refreshFlag := flags.NewBoolFlag("refresh")
+	refreshFlag.SetUsage("Fetch top answerers from Stack Exchange before listing")
+	scmd.AddFlag(refreshFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		tags := so.TrackedTags()
+		if tag, _ := w.Flag("tag"); tag.Present() {
+			tags = []string{tag.Value().String()}
+		}
+		if len(tags) == 0 {
+			w.Fail("--tag must be provided, no tags are tracked")
+			return
+		}
+		period := internal.TopAnswerersAllTime
+		if p, _ := w.Flag("period"); p.Present() {
+			period = p.Value().String()
+		}
+		if period != internal.TopAnswerersMonth && period != internal.TopAnswerersAllTime {
+			w.Fail(fmt.Sprintf("--period must be %s or %s", internal.TopAnswerersMonth, internal.TopAnswerersAllTime))
+			return
+		}
+		if refresh, _ := w.Flag("refresh"); refresh.Present() {
+			for _, tag := range tags {
+				getTopAnswerers(w, so, tag, period)
+			}
+		}
+		for _, tag := range tags {
+			answerers, err := so.DB.StackExchangeTopAnswerers(tag, period)
+			if err != nil {
+				w.Fail(err.Error())
+				return
+			}
+			if len(answerers) == 0 {
+				w.Log.Noticef("No top answerers of %s stored, use --refresh to fetch them.", tag)
+				continue
+			}
+			w.Log.Linef("Top answerers of %s (%s), updated %s", tag, period,
+				answerers[0].Updated.Local().Format("2006-01-02 15:04"))
+			table := internal.NewTable("Rank", "User ID", "Name", "Reputation", "Answers", "Score", "Link")
+			for i, a := range answerers {
+				user := so.DB.FindStackExchangeUser(a.UID)
+				table.AddRow(i+1, a.UID, user.DisplayName, user.Reputation, a.PostCount, a.Score, user.Link)
+			}
+			table.Print()
+		}
+	})
+	return scmd
+}
+
+// StackExchangeUsersHistory returns command printing reputation history of user
+func StackExchangeUsersHistory(so *internal.SlackOverflow) cli.Command {
+	scmd := cli.NewCommand("history")
+	scmd.SetShortDesc("Show recorded reputation history of user.")
+
+	userFlag := flags.NewStringFlag("user")
+	userFlag.SetUsage("Stack Exchange user ID")
+	scmd.AddFlag(userFlag)
+
+	scmd.Do(func(w *cli.Worker) {
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		userArg, _ := w.Flag("user")
+		UID, err := strconv.Atoi(userArg.Value().String())
+		if !userArg.Present() || err != nil {
+			w.Fail("--user must be Stack Exchange user ID")
+			return
+		}
+		user := so.DB.FindStackExchangeUser(UID)
+		if user.UID == 0 {
+			w.Fail(fmt.Sprintf("User %d is not known, users are stored when they ask a question or are top answerers.", UID))
+			return
+		}
+		history, err := so.DB.StackExchangeUserReputationHistory(UID)
+		if err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		w.Log.Linef("%s %s", user.DisplayName, user.Link)
+		table := internal.NewTable("Recorded", "Reputation", "Change")
+		prev := 0
+		for i, rep := range history {
+			change := ""
+			if i > 0 && rep.Reputation != prev {
+				change = strconv.Itoa(rep.Reputation - prev)
+				if rep.Reputation > prev {
+					change = "+" + change
+				}
+			}
+			table.AddRow(rep.Recorded.Local().Format("2006-01-02 15:04"), rep.Reputation, change)
+			prev = rep.Reputation
+		}
+		table.Print()
+	})
+	return scmd
+}
+
+// refreshUsersIfDue refreshes top answerers and users once per users-refresh interval
+func refreshUsersIfDue(w *cli.Worker, so *internal.SlackOverflow) {
+	refreshed := so.DB.StackExchangeUsersRefreshed()
+	if time.Since(refreshed) < so.Config.StackExchange.UsersRefreshInterval() {
+		w.Log.Debugf("Stack Exchange: users refreshed %s, skipping.", refreshed.Local().Format("2006-01-02 15:04"))
+		return
+	}
+	// Top answerers first so that new users get their reputation recorded too
+	refreshTopAnswerers(w, so)
+	refreshUsers(w, so)
+}
+
+// refreshUsers updates all known users with /users/{ids} and records their reputation
+func refreshUsers(w *cli.Worker, so *internal.SlackOverflow) {
+	w.Log.Info("Stack Exchange: Refreshing users.")
+	UIDs, err := so.DB.StackExchangeUserIDs()
+	if err != nil {
+		w.Log.Error(err)
+		return
+	}
+	if len(UIDs) == 0 {
+		w.Log.Debug("Stack Exchange: There are no users to refresh.")
+		return
+	}
+	ids := make([]string, len(UIDs))
+	for i, UID := range UIDs {
+		ids[i] = strconv.Itoa(UID)
+	}
+
+	now := time.Now().UTC()
+	refreshed := 0
+	for _, batch := range internal.BatchIDs(strings.Join(ids, ";"), internal.MaxIDsPerRequest) {
+		users, err := so.Users()
+		if err != nil {
+			w.Log.Error(err)
+			return
+		}
+		pager := so.Pager(users.Pages(batch))
+		pager.MaxPages = 0
+		for pager.Next() {
+			for _, user := range users.Result.Items {
+				if msg, err := so.DB.SyncStackExchangeUserShallowUser(user.ShallowUser()); err != nil {
+					w.Log.Errorf("%s %s", msg, err.Error())
+					continue
+				}
+				if err := so.DB.StackExchangeUserReputationRecord(internal.StackExchangeUserReputation{
+					UID:        user.UID,
+					Recorded:   now,
+					Reputation: user.Reputation,
+				}); err != nil {
+					w.Log.Error(err)
+					continue
+				}
+				refreshed++
+			}
+		}
+		logPager(w, so, pager)
+		if pager.Err() != nil {
+			return
+		}
+	}
+	w.Log.Infof("Stack Exchange: %d of %d users refreshed.", refreshed, len(UIDs))
+}
+
+// refreshTopAnswerers fetches top answerers of all tracked tags
+func refreshTopAnswerers(w *cli.Worker, so *internal.SlackOverflow) {
+	for _, tag := range so.TrackedTags() {
+		for _, period := range []string{internal.TopAnswerersMonth, internal.TopAnswerersAllTime} {
+			if !getTopAnswerers(w, so, tag, period) {
+				return
+			}
+		}
+	}
+}
+
+// getTopAnswerers fetches top answerers of tag and stores them as known users,
+// returns false if request failed
+func getTopAnswerers(w *cli.Worker, so *internal.SlackOverflow, tag string, period string) bool {
+	w.Log.Infof("Stack Exchange: Getting top answerers of %s (%s).", tag, period)
+	topAnswerers, err := so.TagsTopAnswerers()
+	if err != nil {
+		w.Log.Error(err)
+		return false
+	}
+	now := time.Now().UTC()
+	var answerers []internal.StackExchangeTopAnswerer
+	// Single page of at most 30 answerers is enough to find community members
+	pager := so.Pager(topAnswerers.Pages(tag, period))
+	pager.MaxPages = 1
+	for pager.Next() {
+		for _, score := range topAnswerers.Result.Items {
+			if score.User.UID == 0 {
+				continue
+			}
+			if msg, err := so.DB.SyncStackExchangeUserShallowUser(score.User); err != nil {
+				w.Log.Errorf("%s %s", msg, err.Error())
+			}
+			answerers = append(answerers, internal.StackExchangeTopAnswerer{
+				Tag:       tag,
+				Period:    period,
+				UID:       score.User.UID,
+				PostCount: score.PostCount,
+				Score:     score.Score,
+				Updated:   now,
+			})
+		}
+	}
+	logPager(w, so, pager)
+	if pager.Err() != nil {
+		return false
+	}
+	if err := so.DB.StackExchangeTopAnswerersReplace(tag, period, answerers); err != nil {
+		w.Log.Error(err)
+		return false
+	}
+	return true
+}
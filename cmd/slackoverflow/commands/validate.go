@@ -14,7 +14,25 @@ func Validate(so *internal.SlackOverflow) cli.Command {
 	cmd := cli.NewCommand("validate")
 	cmd.SetShortDesc("Validate stackoverflow configuration.")
 	cmd.Do(func(w *cli.Worker) {
-		w.Fail("cmd not implemented.")
+		// Session rejects invalid Stack Exchange query parameters
+		if err := so.Session(w); err != nil {
+			w.Fail(err.Error())
+			return
+		}
+		warnings, err := checkTrackedTags(so)
+		if err != nil {
+			logStackExchangeError(w, so, err)
+			w.Fail("Could not validate tracked tags.")
+			return
+		}
+		for _, warning := range warnings {
+			w.Log.Warning(warning)
+		}
+		if len(warnings) == 0 {
+			w.Log.Ok("Configuration is valid.")
+		} else {
+			w.Log.Warningf("Configuration is valid with %d warning(s).", len(warnings))
+		}
 	})
 	return cmd
 }
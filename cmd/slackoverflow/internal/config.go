@@ -7,6 +7,7 @@ package internal
 import (
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/howi-ce/howi/std/errors"
 	"github.com/nlopes/slack"
@@ -57,13 +58,17 @@ func (c *Config) readConfig() error {
 
 // SlackConfig for Slack Overflow
 type SlackConfig struct {
-	Enabled     bool           `yaml:"enabled"`
-	TeamURL     bool           `yaml:"team-url"`
-	Channel     string         `yaml:"channel"`
-	ChannelName string         `yaml:"channel-name"`
-	Token       string         `yaml:"token"`
-	APIHost     string         `yaml:"api-host"`
-	TeamInfo    slack.TeamInfo `yaml:"team-info"`
+	Enabled       bool           `yaml:"enabled"`
+	TeamURL       bool           `yaml:"team-url"`
+	Channel       string         `yaml:"channel"`
+	ChannelName   string         `yaml:"channel-name"`
+	Token         string         `yaml:"token"`
+	APIHost       string         `yaml:"api-host"`
+	TeamInfo      slack.TeamInfo `yaml:"team-info"`
+	SocketMode    bool           `yaml:"socket-mode"`
+	AppToken      string         `yaml:"app-token"`
+	SigningSecret string         `yaml:"signing-secret"`
+	InboxChannel  string         `yaml:"inbox-channel"`
 }
 
 // Enable posting and updating to Slack
@@ -86,6 +91,11 @@ func (s *SlackConfig) SetToken(t string) {
 	s.Token = t
 }
 
+// SetAppToken sets Slack app-level token used by Socket Mode
+func (s *SlackConfig) SetAppToken(t string) {
+	s.AppToken = t
+}
+
 // SetChannel sets the slack channel where questions will be posted
 func (s *SlackConfig) SetChannel(ch string) {
 	s.Channel = ch
@@ -111,6 +121,31 @@ type StackExchangeConfig struct {
 	QuestionsToWatch int               `yaml:"questions-to-watch"`
 	SearchAdvanced   map[string]string `yaml:"search-advanced"`
 	Questions        map[string]string `yaml:"questions"`
+	// QuestionBody requests body_markdown of questions and posts excerpt to Slack
+	QuestionBody      bool `yaml:"question-body"`
+	BodyExcerptLength int  `yaml:"body-excerpt-length"`
+	// Filters are named filters created with stackexchange filter create
+	Filters map[string]string `yaml:"filters"`
+	// MaxPages fetched per request, QuotaFloor of remaining quota stops paging
+	MaxPages   int `yaml:"max-pages"`
+	QuotaFloor int `yaml:"quota-floor"`
+	// ConcurrentRequests of batched questions/{ids} requests (default 1)
+	ConcurrentRequests int `yaml:"concurrent-requests"`
+	// OAuth app and access token stored by stackexchange login
+	ClientID           string `yaml:"client-id"`
+	ClientSecret       string `yaml:"client-secret"`
+	AccessToken        string `yaml:"access-token"`
+	AccessTokenExpires int64  `yaml:"access-token-expires"`
+	// UsersRefresh is interval in hours of refreshing users and top answerers
+	UsersRefresh int `yaml:"users-refresh"`
+	// MaxLookback is maximum age in hours of new questions fetched after
+	// downtime, older questions are skipped to save quota
+	MaxLookback int `yaml:"max-lookback"`
+	// Realtime subscribes to new questions of tracked tags over WebSocket
+	// while running with --keep-alive, polling is used when disconnected
+	Realtime       bool   `yaml:"realtime"`
+	RealtimeURL    string `yaml:"realtime-url"`
+	RealtimeSiteID int    `yaml:"realtime-site-id"`
 }
 
 // Enable Stack Exchange
@@ -132,3 +167,49 @@ func (s *StackExchangeConfig) SetAPIhost(h string) {
 func (s *StackExchangeConfig) SetAPIVersion(v string) {
 	s.APIVersion = v
 }
+
+// SetFilter stores named filter
+func (s *StackExchangeConfig) SetFilter(name string, filter string) {
+	if s.Filters == nil {
+		s.Filters = make(map[string]string)
+	}
+	s.Filters[name] = filter
+}
+
+// ResolveFilter returns filter stored with given name or value itself when
+// there is no such named filter, so config can refer to filters by name
+func (s *StackExchangeConfig) ResolveFilter(value string) string {
+	if filter, ok := s.Filters[value]; ok {
+		return filter
+	}
+	return value
+}
+
+// AccessTokenExpired returns true if stored access token has expired
+func (s *StackExchangeConfig) AccessTokenExpired(now time.Time) bool {
+	return s.AccessTokenExpires > 0 && now.Unix() >= s.AccessTokenExpires
+}
+
+// ExcerptLength returns maximum length of question body excerpt
+func (s *StackExchangeConfig) ExcerptLength() int {
+	if s.BodyExcerptLength <= 0 {
+		return 300
+	}
+	return s.BodyExcerptLength
+}
+
+// MaxLookbackDuration returns maximum age of new questions, 4 hours by default
+func (s *StackExchangeConfig) MaxLookbackDuration() time.Duration {
+	if s.MaxLookback <= 0 {
+		return 4 * time.Hour
+	}
+	return time.Duration(s.MaxLookback) * time.Hour
+}
+
+// UsersRefreshInterval returns interval of refreshing users, 24 hours by default
+func (s *StackExchangeConfig) UsersRefreshInterval() time.Duration {
+	if s.UsersRefresh <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(s.UsersRefresh) * time.Hour
+}
@@ -37,7 +37,13 @@ const (
   "downVoteCount" INTEGER,
   "deleteVoteCount" INTEGER,
   "favoriteCount" INTEGER,
-  "reOpenVoteCount" INTEGER)`
+  "reOpenVoteCount" INTEGER,
+  "lifecycle" TEXT DEFAULT 'open',
+  "migratedTo" TEXT DEFAULT '',
+  "retaggedAway" INTEGER DEFAULT 0,
+  "bodyMarkdown" TEXT DEFAULT '',
+  "backfilled" INTEGER DEFAULT 0,
+  "acceptedAnswerID" INTEGER DEFAULT 0)`
 
 	stackExchangeUserSchema = `CREATE TABLE IF NOT EXISTS "StackExchangeUser" (
   "UID" INTEGER PRIMARY KEY,
@@ -54,6 +60,126 @@ const (
   "QID" INTEGER,
   "channel" TEXT,
   "ts" TEXT)`
+
+	slackQuestionTriageSchema = `CREATE TABLE IF NOT EXISTS "SlackQuestionTriage" (
+  "QID" INTEGER PRIMARY KEY,
+  "state" TEXT,
+  "user" TEXT,
+  "snoozeUntil" TIMESTAMP,
+  "updated" TIMESTAMP)`
+
+	stackExchangeAnswerSchema = `CREATE TABLE IF NOT EXISTS "StackExchangeAnswer" (
+  "AID" INTEGER PRIMARY KEY,
+  "QID" INTEGER,
+  "UID" INTEGER,
+  "isAccepted" INTEGER,
+  "score" INTEGER,
+  "creationDate" TIMESTAMP,
+  "lastActivityDate" TIMESTAMP,
+  "credited" INTEGER DEFAULT 0,
+  "creditedAccepted" INTEGER DEFAULT 0)`
+
+	slackUserLinkSchema = `CREATE TABLE IF NOT EXISTS "SlackUserLink" (
+  "slackUID" TEXT PRIMARY KEY,
+  "UID" INTEGER,
+  "created" TIMESTAMP)`
+
+	slackSubscriptionSchema = `CREATE TABLE IF NOT EXISTS "SlackSubscription" (
+  "ID" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "slackUID" TEXT,
+  "kind" TEXT,
+  "value" TEXT,
+  "created" TIMESTAMP,
+  "answerCount" INTEGER DEFAULT 0,
+  "isAnswered" INTEGER DEFAULT 0,
+  "closedReason" TEXT DEFAULT '',
+  "acceptedAnswerID" INTEGER DEFAULT 0,
+  "lifecycle" TEXT DEFAULT 'open',
+  UNIQUE ("slackUID", "kind", "value"))`
+
+	stackExchangeQuestionEditSchema = `CREATE TABLE IF NOT EXISTS "StackExchangeQuestionEdit" (
+  "ID" INTEGER PRIMARY KEY AUTOINCREMENT,
+  "QID" INTEGER,
+  "detected" TIMESTAMP,
+  "oldTitle" TEXT,
+  "newTitle" TEXT,
+  "tagsAdded" TEXT,
+  "tagsRemoved" TEXT,
+  "editor" TEXT,
+  "posted" INTEGER DEFAULT 0)`
+
+	stackExchangeInboxItemSchema = `CREATE TABLE IF NOT EXISTS "StackExchangeInboxItem" (
+  "key" TEXT PRIMARY KEY,
+  "source" TEXT,
+  "itemType" TEXT,
+  "title" TEXT,
+  "link" TEXT,
+  "body" TEXT,
+  "site" TEXT,
+  "created" TIMESTAMP,
+  "relayed" INTEGER DEFAULT 0)`
+
+	stackExchangeBackfillSchema = `CREATE TABLE IF NOT EXISTS "StackExchangeBackfill" (
+  "key" TEXT PRIMARY KEY,
+  "site" TEXT,
+  "tagged" TEXT,
+  "fromDate" TIMESTAMP,
+  "toDate" TIMESTAMP,
+  "checkpoint" TIMESTAMP,
+  "questions" INTEGER DEFAULT 0,
+  "updated" TIMESTAMP)`
+
+	stackExchangeUserReputationSchema = `CREATE TABLE IF NOT EXISTS "StackExchangeUserReputation" (
+  "UID" INTEGER,
+  "recorded" TIMESTAMP,
+  "reputation" INTEGER)`
+
+	stackExchangeTopAnswererSchema = `CREATE TABLE IF NOT EXISTS "StackExchangeTopAnswerer" (
+  "tag" TEXT,
+  "period" TEXT,
+  "UID" INTEGER,
+  "postCount" INTEGER,
+  "score" INTEGER,
+  "updated" TIMESTAMP,
+  PRIMARY KEY ("tag", "period", "UID"))`
+
+	stackExchangeSyncCursorSchema = `CREATE TABLE IF NOT EXISTS "StackExchangeSyncCursor" (
+  "feed" TEXT PRIMARY KEY,
+  "lastCreationDate" INTEGER DEFAULT 0,
+  "lastRun" TIMESTAMP,
+  "lastPages" INTEGER DEFAULT 0,
+  "lastItems" INTEGER DEFAULT 0)`
+)
+
+const (
+	// SubscriptionTag matches questions with given tag
+	SubscriptionTag = "tag"
+	// SubscriptionKeyword matches questions with keyword in title
+	SubscriptionKeyword = "keyword"
+	// SubscriptionQuestion follows answers and closing of single question
+	SubscriptionQuestion = "question"
+)
+
+const (
+	// LifecycleOpen question is open
+	LifecycleOpen = "open"
+	// LifecycleClosed question is closed, see ClosedReason
+	LifecycleClosed = "closed"
+	// LifecycleDeleted question is not returned by Stack Exchange API anymore
+	LifecycleDeleted = "deleted"
+	// LifecycleMigrated question was migrated to other site, see MigratedTo
+	LifecycleMigrated = "migrated"
+)
+
+const (
+	// TriageClaimed is set when someone takes the question
+	TriageClaimed = "claimed"
+	// TriageSnoozed is set when question is snoozed
+	TriageSnoozed = "snoozed"
+	// TriageHandled is set when question does not need attention anymore
+	TriageHandled = "handled"
+	// TriageIgnored is set when question is marked as not relevant
+	TriageIgnored = "ignored"
 )
 
 // Database for SlackOverflow
@@ -77,6 +203,25 @@ func (d *Database) VerifyTables(w *cli.Worker) (err error) {
 	if err != nil {
 		return err
 	}
+	// Columns added after initial release
+	if err = d.ensureColumn("StackExchangeQuestion", "lifecycle", "TEXT DEFAULT 'open'"); err != nil {
+		return err
+	}
+	if err = d.ensureColumn("StackExchangeQuestion", "migratedTo", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = d.ensureColumn("StackExchangeQuestion", "retaggedAway", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err = d.ensureColumn("StackExchangeQuestion", "bodyMarkdown", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+	if err = d.ensureColumn("StackExchangeQuestion", "backfilled", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err = d.ensureColumn("StackExchangeQuestion", "acceptedAnswerID", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
 	w.Log.Debug("DB: Stack Exchange Question Schema ok")
 
 	_, err = d.db.Exec(stackExchangeUserSchema)
@@ -91,6 +236,72 @@ func (d *Database) VerifyTables(w *cli.Worker) (err error) {
 	}
 	w.Log.Debug("DB: Slack Question Schema ok")
 
+	_, err = d.db.Exec(slackQuestionTriageSchema)
+	if err != nil {
+		return err
+	}
+	w.Log.Debug("DB: Slack Question Triage Schema ok")
+
+	_, err = d.db.Exec(stackExchangeAnswerSchema)
+	if err != nil {
+		return err
+	}
+	w.Log.Debug("DB: Stack Exchange Answer Schema ok")
+
+	_, err = d.db.Exec(slackUserLinkSchema)
+	if err != nil {
+		return err
+	}
+	w.Log.Debug("DB: Slack User Link Schema ok")
+
+	_, err = d.db.Exec(slackSubscriptionSchema)
+	if err != nil {
+		return err
+	}
+	if err = d.ensureColumn("SlackSubscription", "acceptedAnswerID", "INTEGER DEFAULT 0"); err != nil {
+		return err
+	}
+	if err = d.ensureColumn("SlackSubscription", "lifecycle", "TEXT DEFAULT 'open'"); err != nil {
+		return err
+	}
+	w.Log.Debug("DB: Slack Subscription Schema ok")
+
+	_, err = d.db.Exec(stackExchangeQuestionEditSchema)
+	if err != nil {
+		return err
+	}
+	w.Log.Debug("DB: Stack Exchange Question Edit Schema ok")
+
+	_, err = d.db.Exec(stackExchangeInboxItemSchema)
+	if err != nil {
+		return err
+	}
+	w.Log.Debug("DB: Stack Exchange Inbox Item Schema ok")
+
+	_, err = d.db.Exec(stackExchangeUserReputationSchema)
+	if err != nil {
+		return err
+	}
+	w.Log.Debug("DB: Stack Exchange User Reputation Schema ok")
+
+	_, err = d.db.Exec(stackExchangeTopAnswererSchema)
+	if err != nil {
+		return err
+	}
+	w.Log.Debug("DB: Stack Exchange Top Answerer Schema ok")
+
+	_, err = d.db.Exec(stackExchangeBackfillSchema)
+	if err != nil {
+		return err
+	}
+	w.Log.Debug("DB: Stack Exchange Backfill Schema ok")
+
+	_, err = d.db.Exec(stackExchangeSyncCursorSchema)
+	if err != nil {
+		return err
+	}
+	w.Log.Debug("DB: Stack Exchange Sync Cursor Schema ok")
+
 	return nil
 }
 
@@ -101,27 +312,7 @@ func (d *Database) LatestStackExchangeQuestion() (StackExchangeQuestion, error)
 	if err != nil {
 		return q, err
 	}
-	err = d.db.QueryRow("SELECT * FROM StackExchangeQuestion ORDER BY QID DESC LIMIT 1").Scan(
-		&q.QID,
-		&q.UID,
-		&q.Title,
-		&q.CreationDate,
-		&q.LastActivityDate,
-		&q.ShareLink,
-		&q.ClosedReason,
-		&q.Tags,
-		&q.Site,
-		&q.IsAnswered,
-		&q.Score,
-		&q.ViewCount,
-		&q.AnswerCount,
-		&q.CommentCount,
-		&q.UpVoteCount,
-		&q.DownVoteCount,
-		&q.DeleteVoteCount,
-		&q.FavoriteCount,
-		&q.ReOpenVoteCount,
-	)
+	err = d.db.QueryRow("SELECT * FROM StackExchangeQuestion ORDER BY QID DESC LIMIT 1").Scan(q.fields()...)
 	return q, err
 }
 
@@ -158,8 +349,9 @@ func (d *Database) SyncStackExchangeUserShallowUser(user ShallowUserObj) (msg st
 	return msg, err
 }
 
-// SyncStackExchangeQuestion create or update question received from defined site
-func (d *Database) SyncStackExchangeQuestion(q QuestionObj, site string) (msg string, err error) {
+// SyncStackExchangeQuestion create or update question received from defined site,
+// question is flagged as retagged away if it has none of the tracked tags
+func (d *Database) SyncStackExchangeQuestion(q QuestionObj, site string, trackedTags []string) (msg string, err error) {
 
 	err = d.open()
 	if err != nil {
@@ -180,6 +372,7 @@ func (d *Database) SyncStackExchangeQuestion(q QuestionObj, site string) (msg st
 	seq.Tags = strings.Join(q.Tags, ";")
 	seq.Site = site
 	seq.IsAnswered = q.IsAnswered
+	seq.AcceptedAnswerID = q.AcceptedAnswerID
 	seq.Score = q.Score
 	seq.ViewCount = q.ViewCount
 	seq.AnswerCount = q.AnswerCount
@@ -189,6 +382,14 @@ func (d *Database) SyncStackExchangeQuestion(q QuestionObj, site string) (msg st
 	seq.DeleteVoteCount = q.DeleteVoteCount
 	seq.FavoriteCount = q.FavoriteCount
 	seq.ReOpenVoteCount = q.ReOpenVoteCount
+	seq.Lifecycle, seq.MigratedTo = q.Lifecycle()
+	seq.RetaggedAway = len(trackedTags) > 0 && !q.HasAnyTag(trackedTags)
+	// Keep stored body when filter used for this request did not include it
+	seq.BodyMarkdown = q.BodyMarkdown
+	if seq.BodyMarkdown == "" {
+		seq.BodyMarkdown = existingQuestion.BodyMarkdown
+	}
+	seq.Backfilled = existingQuestion.Backfilled
 
 	// If there is no update needed
 	if existingQuestion == seq {
@@ -216,27 +417,7 @@ func (d *Database) FindStackExchangeQuestion(QID int) StackExchangeQuestion {
 	if err != nil {
 		return q
 	}
-	_ = stmt.QueryRow(QID).Scan(
-		&q.QID,
-		&q.UID,
-		&q.Title,
-		&q.CreationDate,
-		&q.LastActivityDate,
-		&q.ShareLink,
-		&q.ClosedReason,
-		&q.Tags,
-		&q.Site,
-		&q.IsAnswered,
-		&q.Score,
-		&q.ViewCount,
-		&q.AnswerCount,
-		&q.CommentCount,
-		&q.UpVoteCount,
-		&q.DownVoteCount,
-		&q.DeleteVoteCount,
-		&q.FavoriteCount,
-		&q.ReOpenVoteCount,
-	)
+	_ = stmt.QueryRow(QID).Scan(q.fields()...)
 	return q
 }
 
@@ -254,8 +435,9 @@ func (d *Database) StackExchangeQuestionCreate(seq StackExchangeQuestion) (msg s
 	stmt, err := d.db.Prepare(`INSERT INTO StackExchangeQuestion
       (QID, UID, title, creationDate, lastActivityDate, shareLink, closedReason,
         tags, site, isAnswered, score, viewCount, answerCount, commentCount,
-        upVoteCount, downVoteCount, deleteVoteCount, favoriteCount, reOpenVoteCount)
-      VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19);`)
+        upVoteCount, downVoteCount, deleteVoteCount, favoriteCount, reOpenVoteCount,
+        lifecycle, migratedTo, retaggedAway, bodyMarkdown, acceptedAnswerID)
+      VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24);`)
 	defer stmt.Close()
 	if err != nil {
 		return msg, err
@@ -281,6 +463,11 @@ func (d *Database) StackExchangeQuestionCreate(seq StackExchangeQuestion) (msg s
 		seq.DeleteVoteCount,
 		seq.FavoriteCount,
 		seq.ReOpenVoteCount,
+		seq.Lifecycle,
+		seq.MigratedTo,
+		seq.RetaggedAway,
+		seq.BodyMarkdown,
+		seq.AcceptedAnswerID,
 	); err != nil {
 		tx.Rollback()
 		return "Error had to Rollback question table", err
@@ -303,7 +490,8 @@ func (d *Database) StackExchangeQuestionUpdate(seq StackExchangeQuestion) (msg s
 	stmt, err := d.db.Prepare(`UPDATE StackExchangeQuestion SET
     UID=?, title=?, creationDate=?, lastActivityDate=?, shareLink=?, closedReason=?,
       tags=?, site=?, isAnswered=?, score=?, viewCount=?, answerCount=?, commentCount=?,
-      upVoteCount=?, downVoteCount=?, deleteVoteCount=?, favoriteCount=?, reOpenVoteCount=?
+      upVoteCount=?, downVoteCount=?, deleteVoteCount=?, favoriteCount=?, reOpenVoteCount=?,
+      lifecycle=?, migratedTo=?, retaggedAway=?, bodyMarkdown=?, acceptedAnswerID=?
       WHERE QID=?;`)
 	defer stmt.Close()
 	if err != nil {
@@ -329,6 +517,11 @@ func (d *Database) StackExchangeQuestionUpdate(seq StackExchangeQuestion) (msg s
 		seq.DeleteVoteCount,
 		seq.FavoriteCount,
 		seq.ReOpenVoteCount,
+		seq.Lifecycle,
+		seq.MigratedTo,
+		seq.RetaggedAway,
+		seq.BodyMarkdown,
+		seq.AcceptedAnswerID,
 		seq.QID,
 	); err != nil {
 		tx.Rollback()
@@ -537,138 +730,1062 @@ func (d *Database) SlackQuestionGetAll() (links []SlackQuestion, count int) {
 	return links, count
 }
 
-// StackExchangeQuestionTrackedIds return tracked question ids
-func (d *Database) StackExchangeQuestionTrackedIds(qToWatch int) (ids string, count int) {
+// FindSlackQuestionTriage returns triage state of question, State is empty
+// when nobody has triaged the question
+func (d *Database) FindSlackQuestionTriage(QID int) SlackQuestionTriage {
+	t := SlackQuestionTriage{}
 	err := d.open()
 	if err != nil {
-		return
+		return t
+	}
+	stmt, err := d.db.Prepare(`SELECT * FROM SlackQuestionTriage WHERE QID = ?`)
+	if err != nil {
+		return t
 	}
-	count = 0
-	ids = ""
-
-	stmt, err := d.db.Prepare(`SELECT QID FROM StackExchangeQuestion ORDER BY creationDate DESC LIMIT ?`)
 	defer stmt.Close()
+	_ = stmt.QueryRow(QID).Scan(
+		&t.QID,
+		&t.State,
+		&t.User,
+		&t.SnoozeUntil,
+		&t.Updated,
+	)
+	return t
+}
+
+// SlackQuestionTriageSave creates or replaces triage state of question
+func (d *Database) SlackQuestionTriageSave(t SlackQuestionTriage) (msg string, err error) {
+	err = d.open()
 	if err != nil {
-		return ids, count
+		return msg, err
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return msg, err
 	}
-	rows, err := stmt.Query(qToWatch)
+	stmt, err := tx.Prepare(`INSERT OR REPLACE INTO SlackQuestionTriage
+      (QID, state, user, snoozeUntil, updated)
+      VALUES($1,$2,$3,$4,$5);`)
 	if err != nil {
-		return ids, count
+		tx.Rollback()
+		return msg, err
 	}
+	defer stmt.Close()
 
-	var idsMap []string
-	for rows.Next() {
-		var QID string
-		err = rows.Scan(&QID)
-		if err != nil {
-			log.Fatal(err)
-		}
-		// in case of only one question assign that to idsMap
-		ids = QID
-		idsMap = append(idsMap, QID)
-		count++
+	if _, err := stmt.Exec(
+		t.QID,
+		t.State,
+		t.User,
+		t.SnoozeUntil,
+		t.Updated,
+	); err != nil {
+		tx.Rollback()
+		return "Error had to Rollback question triage", err
 	}
-	if count > 1 {
-		ids = strings.Join(idsMap, ";")
+	if err := tx.Commit(); err != nil {
+		return msg, err
 	}
-
-	return ids, count
+	return fmt.Sprintf("Question triage: %d %s.", t.QID, t.State), nil
 }
 
-// StackExchangeQuestionDelete by ID
-func (d *Database) StackExchangeQuestionDelete(seq StackExchangeQuestion) error {
+// SlackQuestionTriageDelete removes triage state of question
+func (d *Database) SlackQuestionTriageDelete(QID int) error {
 	err := d.open()
 	if err != nil {
 		return err
 	}
-	stmt, err := d.db.Prepare(`DELETE FROM StackExchangeQuestion WHERE QID = ?`)
+	stmt, err := d.db.Prepare(`DELETE FROM SlackQuestionTriage WHERE QID = ?`)
 	if err != nil {
 		return err
 	}
-	_, err = stmt.Exec(seq.QID)
 	defer stmt.Close()
+	_, err = stmt.Exec(QID)
 	return err
 }
 
-// Close the database
-func (d *Database) Close() error {
-	return d.db.Close()
+// SlackQuestionTriageSnoozeExpired returns snoozed questions which snooze has expired
+func (d *Database) SlackQuestionTriageSnoozeExpired(now time.Time) (triaged []SlackQuestionTriage, err error) {
+	err = d.open()
+	if err != nil {
+		return triaged, err
+	}
+	stmt, err := d.db.Prepare(`SELECT * FROM SlackQuestionTriage WHERE state = ? AND snoozeUntil <= ?`)
+	if err != nil {
+		return triaged, err
+	}
+	defer stmt.Close()
+	rows, err := stmt.Query(TriageSnoozed, now)
+	if err != nil {
+		return triaged, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		t := SlackQuestionTriage{}
+		if err := rows.Scan(
+			&t.QID,
+			&t.State,
+			&t.User,
+			&t.SnoozeUntil,
+			&t.Updated,
+		); err != nil {
+			return triaged, err
+		}
+		triaged = append(triaged, t)
+	}
+	return triaged, rows.Err()
 }
 
-// StackExchangeQuestionsTracked returns tracked questions
-func (d *Database) StackExchangeQuestionsTracked(qToWatch int) (questions []StackExchangeQuestion, count int) {
-	err := d.open()
+// SyncStackExchangeAnswer create or update answer, credit flags are preserved
+func (d *Database) SyncStackExchangeAnswer(a AnswerObj) (msg string, err error) {
+	err = d.open()
 	if err != nil {
-		return
+		return msg, err
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return msg, err
+	}
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO StackExchangeAnswer
+      (AID, QID, UID, isAccepted, score, creationDate, lastActivityDate)
+      VALUES($1,$2,$3,$4,$5,$6,$7);`)
+	if err != nil {
+		tx.Rollback()
+		return msg, err
 	}
-	count = 0
-
-	stmt, err := d.db.Prepare(`SELECT * FROM StackExchangeQuestion ORDER BY creationDate DESC LIMIT ?`)
 	defer stmt.Close()
+	creationDate := time.Unix(a.CreationDate, 0).UTC()
+	lastActivityDate := time.Unix(a.LastActivityDate, 0).UTC()
+	res, err := stmt.Exec(a.AID, a.QID, a.Owner.UID, a.IsAccepted, a.Score, creationDate, lastActivityDate)
 	if err != nil {
-		return questions, count
+		tx.Rollback()
+		return "Error had to Rollback answer table", err
 	}
-	rows, err := stmt.Query(qToWatch)
+	if created, _ := res.RowsAffected(); created > 0 {
+		return fmt.Sprintf("Answer: %d created.", a.AID), tx.Commit()
+	}
+	upd, err := tx.Prepare(`UPDATE StackExchangeAnswer SET
+    QID=?, UID=?, isAccepted=?, score=?, creationDate=?, lastActivityDate=?
+      WHERE AID=?;`)
 	if err != nil {
-		return questions, count
+		tx.Rollback()
+		return msg, err
+	}
+	defer upd.Close()
+	if _, err := upd.Exec(a.QID, a.Owner.UID, a.IsAccepted, a.Score, creationDate, lastActivityDate, a.AID); err != nil {
+		tx.Rollback()
+		return "Error had to Rollback answer update", err
 	}
+	return fmt.Sprintf("Answer: %d updated.", a.AID), tx.Commit()
+}
 
+// StackExchangeAnswersToCredit returns answers of linked users which have not
+// been announced in Slack yet
+func (d *Database) StackExchangeAnswersToCredit() (answers []StackExchangeAnswerCredit, err error) {
+	err = d.open()
+	if err != nil {
+		return answers, err
+	}
+	rows, err := d.db.Query(`SELECT a.AID, a.QID, a.UID, a.isAccepted, a.credited, a.creditedAccepted, l.slackUID
+      FROM StackExchangeAnswer a JOIN SlackUserLink l ON a.UID = l.UID
+      WHERE a.credited = 0 OR (a.isAccepted = 1 AND a.creditedAccepted = 0)
+      ORDER BY a.creationDate ASC`)
+	if err != nil {
+		return answers, err
+	}
+	defer rows.Close()
 	for rows.Next() {
-		q := StackExchangeQuestion{}
-		err = rows.Scan(
-			&q.QID,
-			&q.UID,
-			&q.Title,
-			&q.CreationDate,
-			&q.LastActivityDate,
-			&q.ShareLink,
-			&q.ClosedReason,
-			&q.Tags,
-			&q.Site,
-			&q.IsAnswered,
-			&q.Score,
-			&q.ViewCount,
-			&q.AnswerCount,
-			&q.CommentCount,
-			&q.UpVoteCount,
-			&q.DownVoteCount,
-			&q.DeleteVoteCount,
-			&q.FavoriteCount,
-			&q.ReOpenVoteCount)
-		if err != nil {
-			log.Fatal(err)
+		a := StackExchangeAnswerCredit{}
+		if err := rows.Scan(
+			&a.AID,
+			&a.QID,
+			&a.UID,
+			&a.IsAccepted,
+			&a.Credited,
+			&a.CreditedAccepted,
+			&a.SlackUID,
+		); err != nil {
+			return answers, err
 		}
-		// in case of only one question assign that to idsMap
-		questions = append(questions, q)
-		count++
+		answers = append(answers, a)
 	}
-
-	return questions, count
+	return answers, rows.Err()
 }
 
-// open database if it is not already open
-func (d *Database) open() (err error) {
-	if d.db != nil {
-		return d.db.Ping()
+// StackExchangeAnswerCredited marks answer as announced in Slack
+func (d *Database) StackExchangeAnswerCredited(AID int, accepted bool) error {
+	err := d.open()
+	if err != nil {
+		return err
 	}
-	d.db, err = sql.Open("sqlite3", d.file.Abs())
+	_, err = d.db.Exec(`UPDATE StackExchangeAnswer SET credited = 1, creditedAccepted = ? WHERE AID = ?`,
+		accepted, AID)
 	return err
 }
 
-// SlackQuestion table
-// Records in this table keep track of qustions between Stack Exchange and Slack
-type SlackQuestion struct {
-	// Id of StackExchangeQuestion question
-	QID     int
-	Channel string
-	TS      string
+// StackExchangeUserAnswersCredited marks all stored answers of user as
+// announced in Slack without announcing them
+func (d *Database) StackExchangeUserAnswersCredited(UID int) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`UPDATE StackExchangeAnswer SET credited = 1, creditedAccepted = isAccepted WHERE UID = ?`,
+		UID)
+	return err
 }
 
-// StackExchangeQuestion table
-type StackExchangeQuestion struct {
-	QID              int
-	UID              int
-	Title            string
+// Leaderboard returns answer counts of linked users since given time
+func (d *Database) Leaderboard(since time.Time) (board []LeaderboardRow, err error) {
+	err = d.open()
+	if err != nil {
+		return board, err
+	}
+	rows, err := d.db.Query(`SELECT l.slackUID, l.UID, COALESCE(u.displayName, ''),
+        COUNT(a.AID), COALESCE(SUM(a.isAccepted), 0), COALESCE(SUM(a.score), 0)
+      FROM SlackUserLink l
+      JOIN StackExchangeAnswer a ON a.UID = l.UID AND a.creationDate >= ?
+      LEFT JOIN StackExchangeUser u ON u.UID = l.UID
+      GROUP BY l.slackUID, l.UID
+      ORDER BY COUNT(a.AID) DESC, SUM(a.isAccepted) DESC`, since)
+	if err != nil {
+		return board, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		r := LeaderboardRow{}
+		if err := rows.Scan(
+			&r.SlackUID,
+			&r.UID,
+			&r.DisplayName,
+			&r.Answers,
+			&r.Accepted,
+			&r.Score,
+		); err != nil {
+			return board, err
+		}
+		board = append(board, r)
+	}
+	return board, rows.Err()
+}
+
+// SlackUserLinkSave links Slack user to Stack Exchange user
+func (d *Database) SlackUserLinkSave(link SlackUserLink) (msg string, err error) {
+	err = d.open()
+	if err != nil {
+		return msg, err
+	}
+	if link.Created.IsZero() {
+		link.Created = time.Now().UTC()
+	}
+	_, err = d.db.Exec(`INSERT OR REPLACE INTO SlackUserLink (slackUID, UID, created) VALUES($1,$2,$3);`,
+		link.SlackUID, link.UID, link.Created)
+	if err != nil {
+		return msg, err
+	}
+	return fmt.Sprintf("Slack user %s linked to Stack Exchange user %d.", link.SlackUID, link.UID), nil
+}
+
+// SlackUserLinkDelete removes link of Slack user
+func (d *Database) SlackUserLinkDelete(slackUID string) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`DELETE FROM SlackUserLink WHERE slackUID = ?`, slackUID)
+	return err
+}
+
+// SlackUserLinkGetAll returns all linked users
+func (d *Database) SlackUserLinkGetAll() (links []SlackUserLink, err error) {
+	err = d.open()
+	if err != nil {
+		return links, err
+	}
+	rows, err := d.db.Query(`SELECT slackUID, UID, created FROM SlackUserLink ORDER BY created ASC`)
+	if err != nil {
+		return links, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		l := SlackUserLink{}
+		if err := rows.Scan(&l.SlackUID, &l.UID, &l.Created); err != nil {
+			return links, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
+
+// SlackSubscriptionCreate subscribes Slack user, existing subscription is kept as is
+func (d *Database) SlackSubscriptionCreate(sub SlackSubscription) (msg string, err error) {
+	err = d.open()
+	if err != nil {
+		return msg, err
+	}
+	if sub.Created.IsZero() {
+		sub.Created = time.Now().UTC()
+	}
+	res, err := d.db.Exec(`INSERT OR IGNORE INTO SlackSubscription
+      (slackUID, kind, value, created, answerCount, isAnswered, closedReason, acceptedAnswerID, lifecycle)
+      VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9);`,
+		sub.SlackUID,
+		sub.Kind,
+		sub.Value,
+		sub.Created,
+		sub.AnswerCount,
+		sub.IsAnswered,
+		sub.ClosedReason,
+		sub.AcceptedAnswerID,
+		sub.Lifecycle,
+	)
+	if err != nil {
+		return msg, err
+	}
+	if created, _ := res.RowsAffected(); created == 0 {
+		return fmt.Sprintf("%s is already subscribed to %s %s.", sub.SlackUID, sub.Kind, sub.Value), nil
+	}
+	return fmt.Sprintf("%s subscribed to %s %s.", sub.SlackUID, sub.Kind, sub.Value), nil
+}
+
+// SlackSubscriptionDelete unsubscribes Slack user, returns false if there was no such subscription
+func (d *Database) SlackSubscriptionDelete(slackUID string, kind string, value string) (bool, error) {
+	err := d.open()
+	if err != nil {
+		return false, err
+	}
+	res, err := d.db.Exec(`DELETE FROM SlackSubscription WHERE slackUID = ? AND kind = ? AND value = ?`,
+		slackUID, kind, value)
+	if err != nil {
+		return false, err
+	}
+	deleted, _ := res.RowsAffected()
+	return deleted > 0, nil
+}
+
+// SlackSubscriptionGetAll returns subscriptions of Slack user or all subscriptions if slackUID is empty
+func (d *Database) SlackSubscriptionGetAll(slackUID string) ([]SlackSubscription, error) {
+	if slackUID == "" {
+		return d.querySlackSubscriptions(`SELECT * FROM SlackSubscription ORDER BY slackUID, kind, value`)
+	}
+	return d.querySlackSubscriptions(`SELECT * FROM SlackSubscription WHERE slackUID = ? ORDER BY kind, value`, slackUID)
+}
+
+// SlackSubscriptionsByKind returns all subscriptions of given kind
+func (d *Database) SlackSubscriptionsByKind(kind string) ([]SlackSubscription, error) {
+	return d.querySlackSubscriptions(`SELECT * FROM SlackSubscription WHERE kind = ?`, kind)
+}
+
+// SlackSubscriptionUpdateState stores last notified state of subscribed question
+func (d *Database) SlackSubscriptionUpdateState(sub SlackSubscription) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`UPDATE SlackSubscription SET answerCount = ?, isAnswered = ?, closedReason = ?,
+      acceptedAnswerID = ?, lifecycle = ? WHERE ID = ?`,
+		sub.AnswerCount, sub.IsAnswered, sub.ClosedReason, sub.AcceptedAnswerID, sub.Lifecycle, sub.ID)
+	return err
+}
+
+func (d *Database) querySlackSubscriptions(query string, args ...interface{}) (subs []SlackSubscription, err error) {
+	err = d.open()
+	if err != nil {
+		return subs, err
+	}
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return subs, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		sub := SlackSubscription{}
+		if err := rows.Scan(
+			&sub.ID,
+			&sub.SlackUID,
+			&sub.Kind,
+			&sub.Value,
+			&sub.Created,
+			&sub.AnswerCount,
+			&sub.IsAnswered,
+			&sub.ClosedReason,
+			&sub.AcceptedAnswerID,
+			&sub.Lifecycle,
+		); err != nil {
+			return subs, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// StackExchangeQuestionTrackedIds return tracked question ids
+func (d *Database) StackExchangeQuestionTrackedIds(qToWatch int) (ids string, count int) {
+	err := d.open()
+	if err != nil {
+		return
+	}
+	count = 0
+	ids = ""
+
+	// Deleted and migrated questions are not returned by the site anymore
+	stmt, err := d.db.Prepare(`SELECT QID FROM StackExchangeQuestion WHERE lifecycle NOT IN (?, ?)
+      ORDER BY creationDate DESC LIMIT ?`)
+	defer stmt.Close()
+	if err != nil {
+		return ids, count
+	}
+	rows, err := stmt.Query(LifecycleDeleted, LifecycleMigrated, qToWatch)
+	if err != nil {
+		return ids, count
+	}
+
+	var idsMap []string
+	for rows.Next() {
+		var QID string
+		err = rows.Scan(&QID)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// in case of only one question assign that to idsMap
+		ids = QID
+		idsMap = append(idsMap, QID)
+		count++
+	}
+	if count > 1 {
+		ids = strings.Join(idsMap, ";")
+	}
+
+	return ids, count
+}
+
+// StackExchangeQuestionDelete by ID
+func (d *Database) StackExchangeQuestionDelete(seq StackExchangeQuestion) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	stmt, err := d.db.Prepare(`DELETE FROM StackExchangeQuestion WHERE QID = ?`)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(seq.QID)
+	defer stmt.Close()
+	return err
+}
+
+// StackExchangeQuestionDeleted marks question as deleted on Stack Exchange
+func (d *Database) StackExchangeQuestionDeleted(QID int) (msg string, err error) {
+	err = d.open()
+	if err != nil {
+		return msg, err
+	}
+	res, err := d.db.Exec(`UPDATE StackExchangeQuestion SET lifecycle = ? WHERE QID = ? AND lifecycle != ?`,
+		LifecycleDeleted, QID, LifecycleDeleted)
+	if err != nil {
+		return msg, err
+	}
+	if updated, _ := res.RowsAffected(); updated == 0 {
+		return fmt.Sprintf("Question: %d is already marked as deleted.", QID), nil
+	}
+	return fmt.Sprintf("Question: %d marked as deleted.", QID), nil
+}
+
+// StackExchangeQuestionEditCreate stores detected edit of question
+func (d *Database) StackExchangeQuestionEditCreate(edit StackExchangeQuestionEdit) (msg string, err error) {
+	err = d.open()
+	if err != nil {
+		return msg, err
+	}
+	_, err = d.db.Exec(`INSERT INTO StackExchangeQuestionEdit
+      (QID, detected, oldTitle, newTitle, tagsAdded, tagsRemoved, editor, posted)
+      VALUES($1,$2,$3,$4,$5,$6,$7,$8);`,
+		edit.QID,
+		edit.Detected,
+		edit.OldTitle,
+		edit.NewTitle,
+		strings.Join(edit.TagsAdded, ";"),
+		strings.Join(edit.TagsRemoved, ";"),
+		edit.Editor,
+		edit.Posted,
+	)
+	if err != nil {
+		return msg, err
+	}
+	return fmt.Sprintf("Question: %d edit recorded.", edit.QID), nil
+}
+
+// StackExchangeQuestionEditsNotPosted returns edits which are not posted to Slack yet
+func (d *Database) StackExchangeQuestionEditsNotPosted() (edits []StackExchangeQuestionEdit, err error) {
+	err = d.open()
+	if err != nil {
+		return edits, err
+	}
+	rows, err := d.db.Query(`SELECT * FROM StackExchangeQuestionEdit WHERE posted = 0 ORDER BY detected ASC`)
+	if err != nil {
+		return edits, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		edit := StackExchangeQuestionEdit{}
+		var added, removed string
+		if err := rows.Scan(
+			&edit.ID,
+			&edit.QID,
+			&edit.Detected,
+			&edit.OldTitle,
+			&edit.NewTitle,
+			&added,
+			&removed,
+			&edit.Editor,
+			&edit.Posted,
+		); err != nil {
+			return edits, err
+		}
+		edit.TagsAdded = splitTags(added)
+		edit.TagsRemoved = splitTags(removed)
+		edits = append(edits, edit)
+	}
+	return edits, rows.Err()
+}
+
+// StackExchangeQuestionEditPosted marks edit as posted to Slack
+func (d *Database) StackExchangeQuestionEditPosted(ID int) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`UPDATE StackExchangeQuestionEdit SET posted = 1 WHERE ID = ?`, ID)
+	return err
+}
+
+// StackExchangeInboxItemCreate stores inbox item or notification, already
+// stored items are ignored
+func (d *Database) StackExchangeInboxItemCreate(item StackExchangeInboxItem) (created bool, err error) {
+	err = d.open()
+	if err != nil {
+		return false, err
+	}
+	res, err := d.db.Exec(`INSERT OR IGNORE INTO StackExchangeInboxItem
+      (key, source, itemType, title, link, body, site, created, relayed)
+      VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9);`,
+		item.Key,
+		item.Source,
+		item.ItemType,
+		item.Title,
+		item.Link,
+		item.Body,
+		item.Site,
+		item.Created,
+		item.Relayed,
+	)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
+
+// StackExchangeInboxItemsNotRelayed returns inbox items which are not relayed to Slack yet
+func (d *Database) StackExchangeInboxItemsNotRelayed() (items []StackExchangeInboxItem, err error) {
+	err = d.open()
+	if err != nil {
+		return items, err
+	}
+	rows, err := d.db.Query(`SELECT * FROM StackExchangeInboxItem WHERE relayed = 0 ORDER BY created ASC`)
+	if err != nil {
+		return items, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item := StackExchangeInboxItem{}
+		if err := rows.Scan(
+			&item.Key,
+			&item.Source,
+			&item.ItemType,
+			&item.Title,
+			&item.Link,
+			&item.Body,
+			&item.Site,
+			&item.Created,
+			&item.Relayed,
+		); err != nil {
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// StackExchangeInboxItemRelayed marks inbox item as relayed to Slack
+func (d *Database) StackExchangeInboxItemRelayed(key string) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`UPDATE StackExchangeInboxItem SET relayed = 1 WHERE key = ?`, key)
+	return err
+}
+
+// StackExchangeUserIDs returns IDs of all known Stack Exchange users
+func (d *Database) StackExchangeUserIDs() (ids []int, err error) {
+	err = d.open()
+	if err != nil {
+		return ids, err
+	}
+	rows, err := d.db.Query(`SELECT UID FROM StackExchangeUser ORDER BY UID ASC`)
+	if err != nil {
+		return ids, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var UID int
+		if err := rows.Scan(&UID); err != nil {
+			return ids, err
+		}
+		ids = append(ids, UID)
+	}
+	return ids, rows.Err()
+}
+
+// StackExchangeUserReputationRecord stores reputation of user at given time
+func (d *Database) StackExchangeUserReputationRecord(rep StackExchangeUserReputation) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`INSERT INTO StackExchangeUserReputation
+      (UID, recorded, reputation)
+      VALUES($1,$2,$3);`,
+		rep.UID,
+		rep.Recorded,
+		rep.Reputation,
+	)
+	return err
+}
+
+// StackExchangeUserReputationHistory returns reputation history of user, oldest first
+func (d *Database) StackExchangeUserReputationHistory(UID int) (history []StackExchangeUserReputation, err error) {
+	err = d.open()
+	if err != nil {
+		return history, err
+	}
+	rows, err := d.db.Query(`SELECT * FROM StackExchangeUserReputation WHERE UID = ? ORDER BY recorded ASC`, UID)
+	if err != nil {
+		return history, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		rep := StackExchangeUserReputation{}
+		if err := rows.Scan(&rep.UID, &rep.Recorded, &rep.Reputation); err != nil {
+			return history, err
+		}
+		history = append(history, rep)
+	}
+	return history, rows.Err()
+}
+
+// StackExchangeUsersRefreshed returns time of last users refresh, zero if
+// users have never been refreshed
+func (d *Database) StackExchangeUsersRefreshed() time.Time {
+	var refreshed time.Time
+	err := d.open()
+	if err != nil {
+		return refreshed
+	}
+	_ = d.db.QueryRow(`SELECT recorded FROM StackExchangeUserReputation
+      ORDER BY recorded DESC LIMIT 1`).Scan(&refreshed)
+	return refreshed
+}
+
+// StackExchangeTopAnswerersReplace replaces top answerers of tag for given period
+func (d *Database) StackExchangeTopAnswerersReplace(tag string, period string, answerers []StackExchangeTopAnswerer) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM StackExchangeTopAnswerer WHERE tag = ? AND period = ?`, tag, period); err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, a := range answerers {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO StackExchangeTopAnswerer
+      (tag, period, UID, postCount, score, updated)
+      VALUES($1,$2,$3,$4,$5,$6);`,
+			tag,
+			period,
+			a.UID,
+			a.PostCount,
+			a.Score,
+			a.Updated,
+		); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// StackExchangeTopAnswerers returns top answerers of tag for given period ordered by score
+func (d *Database) StackExchangeTopAnswerers(tag string, period string) (answerers []StackExchangeTopAnswerer, err error) {
+	err = d.open()
+	if err != nil {
+		return answerers, err
+	}
+	rows, err := d.db.Query(`SELECT * FROM StackExchangeTopAnswerer
+      WHERE tag = ? AND period = ? ORDER BY score DESC, postCount DESC`, tag, period)
+	if err != nil {
+		return answerers, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		a := StackExchangeTopAnswerer{}
+		if err := rows.Scan(
+			&a.Tag,
+			&a.Period,
+			&a.UID,
+			&a.PostCount,
+			&a.Score,
+			&a.Updated,
+		); err != nil {
+			return answerers, err
+		}
+		answerers = append(answerers, a)
+	}
+	return answerers, rows.Err()
+}
+
+// StackExchangeQuestionBackfilled marks question as backfilled so that it is
+// not posted to Slack as new question
+func (d *Database) StackExchangeQuestionBackfilled(QID int) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`UPDATE StackExchangeQuestion SET backfilled = 1 WHERE QID = ?`, QID)
+	return err
+}
+
+// FindStackExchangeBackfill returns checkpoint of backfill by key, zero
+// value if backfill has not been started
+func (d *Database) FindStackExchangeBackfill(key string) StackExchangeBackfill {
+	b := StackExchangeBackfill{}
+	err := d.open()
+	if err != nil {
+		return b
+	}
+	_ = d.db.QueryRow(`SELECT * FROM StackExchangeBackfill WHERE key = ?`, key).Scan(
+		&b.Key,
+		&b.Site,
+		&b.Tagged,
+		&b.FromDate,
+		&b.ToDate,
+		&b.Checkpoint,
+		&b.Questions,
+		&b.Updated,
+	)
+	return b
+}
+
+// StackExchangeBackfillSave creates or updates backfill checkpoint
+func (d *Database) StackExchangeBackfillSave(b StackExchangeBackfill) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	_, err = d.db.Exec(`INSERT OR REPLACE INTO StackExchangeBackfill
+      (key, site, tagged, fromDate, toDate, checkpoint, questions, updated)
+      VALUES($1,$2,$3,$4,$5,$6,$7,$8);`,
+		b.Key,
+		b.Site,
+		b.Tagged,
+		b.FromDate,
+		b.ToDate,
+		b.Checkpoint,
+		b.Questions,
+		b.Updated,
+	)
+	return err
+}
+
+// FindStackExchangeSyncCursor returns sync cursor of feed, zero value if
+// feed has not been synced yet
+func (d *Database) FindStackExchangeSyncCursor(feed string) StackExchangeSyncCursor {
+	c := StackExchangeSyncCursor{}
+	err := d.open()
+	if err != nil {
+		return c
+	}
+	var lastCreationDate int64
+	err = d.db.QueryRow(`SELECT * FROM StackExchangeSyncCursor WHERE feed = ?`, feed).Scan(
+		&c.Feed,
+		&lastCreationDate,
+		&c.LastRun,
+		&c.LastPages,
+		&c.LastItems,
+	)
+	if err != nil {
+		return StackExchangeSyncCursor{}
+	}
+	c.LastCreationDate = time.Unix(lastCreationDate, 0).UTC()
+	return c
+}
+
+// StackExchangeSyncCursorAdvance records run of feed. Last creation date is
+// only moved forward so that concurrent or replayed runs never rewind it.
+func (d *Database) StackExchangeSyncCursorAdvance(c StackExchangeSyncCursor) error {
+	err := d.open()
+	if err != nil {
+		return err
+	}
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`INSERT OR IGNORE INTO StackExchangeSyncCursor (feed) VALUES(?)`, c.Feed); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err = tx.Exec(`UPDATE StackExchangeSyncCursor SET
+      lastCreationDate = MAX(lastCreationDate, ?), lastRun = ?, lastPages = ?, lastItems = ?
+      WHERE feed = ?`,
+		c.LastCreationDate.Unix(),
+		c.LastRun,
+		c.LastPages,
+		c.LastItems,
+		c.Feed,
+	); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// Close the database
+func (d *Database) Close() error {
+	return d.db.Close()
+}
+
+// StackExchangeQuestionsTracked returns tracked questions
+func (d *Database) StackExchangeQuestionsTracked(qToWatch int) (questions []StackExchangeQuestion, count int) {
+	err := d.open()
+	if err != nil {
+		return
+	}
+	count = 0
+
+	stmt, err := d.db.Prepare(`SELECT * FROM StackExchangeQuestion WHERE lifecycle NOT IN (?, ?)
+      ORDER BY creationDate DESC LIMIT ?`)
+	defer stmt.Close()
+	if err != nil {
+		return questions, count
+	}
+	rows, err := stmt.Query(LifecycleDeleted, LifecycleMigrated, qToWatch)
+	if err != nil {
+		return questions, count
+	}
+
+	for rows.Next() {
+		q := StackExchangeQuestion{}
+		err = rows.Scan(q.fields()...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// in case of only one question assign that to idsMap
+		questions = append(questions, q)
+		count++
+	}
+
+	return questions, count
+}
+
+// ensureColumn adds column to table created by older version of SlackOverflow
+func (d *Database) ensureColumn(table string, column string, definition string) error {
+	rows, err := d.db.Query(`PRAGMA table_info("` + table + `")`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			cid     int
+			name    string
+			ctype   string
+			notnull int
+			dflt    sql.NullString
+			pk      int
+		)
+		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dflt, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+	_, err = d.db.Exec(`ALTER TABLE "` + table + `" ADD COLUMN "` + column + `" ` + definition)
+	return err
+}
+
+// open database if it is not already open
+func (d *Database) open() (err error) {
+	if d.db != nil {
+		return d.db.Ping()
+	}
+	d.db, err = sql.Open("sqlite3", d.file.Abs())
+	return err
+}
+
+// SlackQuestion table
+// Records in this table keep track of qustions between Stack Exchange and Slack
+type SlackQuestion struct {
+	// Id of StackExchangeQuestion question
+	QID     int
+	Channel string
+	TS      string
+}
+
+// SlackQuestionTriage table
+// Records in this table keep track of what team has decided to do with question
+type SlackQuestionTriage struct {
+	// Id of StackExchangeQuestion question
+	QID         int
+	State       string
+	User        string
+	SnoozeUntil time.Time
+	Updated     time.Time
+}
+
+// StackExchangeQuestionEdit table
+// Records in this table keep track of title and tag changes of tracked questions
+type StackExchangeQuestionEdit struct {
+	ID          int
+	QID         int
+	Detected    time.Time
+	OldTitle    string
+	NewTitle    string
+	TagsAdded   []string
+	TagsRemoved []string
+	Editor      string
+	Posted      bool
+}
+
+// StackExchangeInboxItem table
+// Records in this table dedupe inbox items and notifications of the Stack
+// Exchange account relayed to Slack
+type StackExchangeInboxItem struct {
+	Key      string
+	Source   string
+	ItemType string
+	Title    string
+	Link     string
+	Body     string
+	Site     string
+	Created  time.Time
+	Relayed  bool
+}
+
+// StackExchangeBackfill table
+// Records in this table keep checkpoint of backfill so that interrupted
+// backfill resumes from last completed date window
+type StackExchangeBackfill struct {
+	Key        string
+	Site       string
+	Tagged     string
+	FromDate   time.Time
+	ToDate     time.Time
+	Checkpoint time.Time
+	Questions  int
+	Updated    time.Time
+}
+
+// StackExchangeSyncCursor table
+// Records in this table keep position of new question feeds, questions
+// created after LastCreationDate are fetched on next run
+type StackExchangeSyncCursor struct {
+	Feed             string
+	LastCreationDate time.Time
+	LastRun          time.Time
+	LastPages        int
+	LastItems        int
+}
+
+// StackExchangeUserReputation table
+// Records in this table keep reputation history of known Stack Exchange users
+type StackExchangeUserReputation struct {
+	UID        int
+	Recorded   time.Time
+	Reputation int
+}
+
+// StackExchangeTopAnswerer table
+// Records in this table keep top answerers of tracked tags
+type StackExchangeTopAnswerer struct {
+	Tag       string
+	Period    string
+	UID       int
+	PostCount int
+	Score     int
+	Updated   time.Time
+}
+
+// SlackUserLink table
+// Records in this table map Slack users to their Stack Exchange accounts
+type SlackUserLink struct {
+	SlackUID string
+	UID      int
+	Created  time.Time
+}
+
+// SlackSubscription table
+// Records in this table keep track of what Slack users want to receive as direct message.
+// AnswerCount, IsAnswered, ClosedReason, AcceptedAnswerID and Lifecycle hold last
+// notified state of subscribed question.
+type SlackSubscription struct {
+	ID               int
+	SlackUID         string
+	Kind             string
+	Value            string
+	Created          time.Time
+	AnswerCount      int
+	IsAnswered       bool
+	ClosedReason     string
+	AcceptedAnswerID int
+	Lifecycle        string
+}
+
+// StackExchangeAnswerCredit is answer of linked user waiting to be announced
+type StackExchangeAnswerCredit struct {
+	AID              int
+	QID              int
+	UID              int
+	IsAccepted       bool
+	Credited         bool
+	CreditedAccepted bool
+	SlackUID         string
+}
+
+// LeaderboardRow is linked users answer stats
+type LeaderboardRow struct {
+	SlackUID    string
+	UID         int
+	DisplayName string
+	Answers     int
+	Accepted    int
+	Score       int
+}
+
+// StackExchangeQuestion table
+type StackExchangeQuestion struct {
+	QID              int
+	UID              int
+	Title            string
 	CreationDate     time.Time
 	LastActivityDate time.Time
 	ShareLink        string
@@ -685,6 +1802,45 @@ type StackExchangeQuestion struct {
 	DeleteVoteCount  int
 	FavoriteCount    int
 	ReOpenVoteCount  int
+	Lifecycle        string
+	MigratedTo       string
+	RetaggedAway     bool
+	BodyMarkdown     string
+	// Backfilled questions are stored by stackexchange backfill and not posted to Slack
+	Backfilled bool
+	// AcceptedAnswerID is 0 until asker accepts an answer
+	AcceptedAnswerID int
+}
+
+// fields of StackExchangeQuestion in order of table columns
+func (q *StackExchangeQuestion) fields() []interface{} {
+	return []interface{}{
+		&q.QID,
+		&q.UID,
+		&q.Title,
+		&q.CreationDate,
+		&q.LastActivityDate,
+		&q.ShareLink,
+		&q.ClosedReason,
+		&q.Tags,
+		&q.Site,
+		&q.IsAnswered,
+		&q.Score,
+		&q.ViewCount,
+		&q.AnswerCount,
+		&q.CommentCount,
+		&q.UpVoteCount,
+		&q.DownVoteCount,
+		&q.DeleteVoteCount,
+		&q.FavoriteCount,
+		&q.ReOpenVoteCount,
+		&q.Lifecycle,
+		&q.MigratedTo,
+		&q.RetaggedAway,
+		&q.BodyMarkdown,
+		&q.Backfilled,
+		&q.AcceptedAnswerID,
+	}
 }
 
 // StackExchangeUser table
@@ -0,0 +1,223 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/lib/filesystem/path"
+	"github.com/nlopes/slack"
+)
+
+// dryRunTextLen is max length of rendered text in dry run table
+const dryRunTextLen = 80
+
+// DryRunAction is action which would be taken without --dry-run
+type DryRunAction struct {
+	Action  string
+	Channel string
+	Text    string
+}
+
+// DryRun records actions instead of taking them. Reads are made as usual
+// and database writes go to scratch copy of database, so that rules see
+// the same state as they would on real run. Methods of nil DryRun are no-op.
+type DryRun struct {
+	mu      sync.Mutex
+	actions []DryRunAction
+	dbCopy  string
+	seq     int
+}
+
+// Enabled returns true when running with --dry-run
+func (d *DryRun) Enabled() bool {
+	return d != nil
+}
+
+// Add action with channel and rendered text
+func (d *DryRun) Add(action string, channel string, text string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.actions = append(d.actions, DryRunAction{Action: action, Channel: channel, Text: text})
+}
+
+// Actions recorded since last Print
+func (d *DryRun) Actions() []DryRunAction {
+	if d == nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]DryRunAction(nil), d.actions...)
+}
+
+// Print table of recorded actions and forget them
+func (d *DryRun) Print(w *cli.Worker) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	actions := d.actions
+	d.actions = nil
+	d.mu.Unlock()
+	if len(actions) == 0 {
+		w.Log.Notice("Dry run: no actions would be taken.")
+		return
+	}
+	w.Log.Noticef("Dry run: %d actions would be taken.", len(actions))
+	table := NewTable("Action", "Channel", "Text")
+	for _, a := range actions {
+		table.AddRow(a.Action, a.Channel, dryRunText(a.Text))
+	}
+	table.Print()
+}
+
+// Close removes scratch copy of database
+func (d *DryRun) Close() error {
+	if d == nil || d.dbCopy == "" {
+		return nil
+	}
+	return os.Remove(d.dbCopy)
+}
+
+// nextTS returns fake timestamp of message which would be posted
+func (d *DryRun) nextTS() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seq++
+	return fmt.Sprintf("dry-run.%06d", d.seq)
+}
+
+// dryRun enables --dry-run and points database to scratch copy of it
+func (so *SlackOverflow) dryRun(w *cli.Worker) error {
+	f, _ := w.Flag("dry-run")
+	if !f.Present() {
+		return nil
+	}
+	if so.DryRun == nil {
+		dbCopy, err := copyDatabase(so.DatabaseFilePath)
+		if err != nil {
+			return err
+		}
+		so.DryRun = &DryRun{dbCopy: dbCopy}
+		w.Log.Notice("Dry run: nothing is posted to Slack and database changes are discarded.")
+	}
+	dbPath, err := path.New(so.DryRun.dbCopy)
+	if err != nil {
+		return err
+	}
+	so.DB.SetPath(dbPath)
+	return nil
+}
+
+// copyDatabase copies database file to temporary file, file is empty if
+// database does not exist yet
+func copyDatabase(file path.Obj) (string, error) {
+	dst, err := ioutil.TempFile("", "slackoverflow-dry-run-")
+	if err != nil {
+		return "", err
+	}
+	defer dst.Close()
+	if !file.Exists() {
+		return dst.Name(), nil
+	}
+	src, err := os.Open(file.Abs())
+	if err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+	defer src.Close()
+	if _, err := io.Copy(dst, src); err != nil {
+		os.Remove(dst.Name())
+		return "", err
+	}
+	return dst.Name(), nil
+}
+
+// dryRunText returns text on single line shortened for table
+func dryRunText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > dryRunTextLen {
+		text = string(r[:dryRunTextLen-1]) + "…"
+	}
+	return text
+}
+
+// SlackClient is Slack API client which records messages it would post or
+// update instead of sending them when dry run is enabled
+type SlackClient struct {
+	*slack.Client
+	dryRun *DryRun
+}
+
+// PostMessage to channel
+func (c *SlackClient) PostMessage(channel string, text string, params slack.PostMessageParameters) (string, string, error) {
+	if c.dryRun.Enabled() {
+		c.dryRun.Add("post", channel, messageText(text, params.Attachments))
+		return channel, c.dryRun.nextTS(), nil
+	}
+	return c.Client.PostMessage(channel, text, params)
+}
+
+// UpdateMessage replaces attachment of message posted at ts
+func (c *SlackClient) UpdateMessage(channel string, ts string, attachment slack.Attachment) (string, error) {
+	return c.updateMessage("update", channel, ts, attachment)
+}
+
+// ArchiveMessage replaces attachment of message posted at ts with short
+// attachment of question not tracked anymore
+func (c *SlackClient) ArchiveMessage(channel string, ts string, attachment slack.Attachment) (string, error) {
+	return c.updateMessage("archive", channel, ts, attachment)
+}
+
+func (c *SlackClient) updateMessage(action string, channel string, ts string, attachment slack.Attachment) (string, error) {
+	if c.dryRun.Enabled() {
+		c.dryRun.Add(action, channel, messageText("", []slack.Attachment{attachment}))
+		return channel, nil
+	}
+	channelID, _, _, err := c.Client.SendMessage(channel,
+		slack.MsgOptionUpdate(ts),
+		slack.MsgOptionAsUser(false),
+		slack.MsgOptionAttachments(attachment),
+	)
+	return channelID, err
+}
+
+// OpenIMChannel with user, on dry run user ID is returned as channel
+func (c *SlackClient) OpenIMChannel(user string) (bool, bool, string, error) {
+	if c.dryRun.Enabled() {
+		return false, false, user, nil
+	}
+	return c.Client.OpenIMChannel(user)
+}
+
+// messageText renders text and attachments of message as plain text
+func messageText(text string, attachments []slack.Attachment) string {
+	var parts []string
+	if text != "" {
+		parts = append(parts, text)
+	}
+	for _, a := range attachments {
+		title := a.Title
+		if title == "" {
+			title = a.Fallback
+		}
+		for _, part := range []string{a.Pretext, title, a.Text} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+	return strings.Join(parts, " / ")
+}
@@ -0,0 +1,91 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestQuestionFilter(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/2.2/filters/create" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[{"filter":"!test","filter_type":"safe"}],"quota_max":300,"quota_remaining":299}`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "slackoverflow-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "slackoverflow.yaml")
+	newSlackOverflow := func() *SlackOverflow {
+		so := NewSlackOverflow()
+		so.Config.file = file
+		so.StackExchange.SetHost(server.URL)
+		so.StackExchange.SetAPIVersion("2.2")
+		return so
+	}
+	name := FilterName(QuestionFilterPrefix, QuestionFilterFields(false))
+
+	// Dry run neither creates filter nor saves config
+	so := newSlackOverflow()
+	so.DryRun = &DryRun{}
+	so.QuestionFilter(newTestWorker())
+	so.QuestionFilter(newTestWorker())
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("%d requests made on dry run, want 0", n)
+	}
+	if got := so.StackExchange.GetQuestionFilter(); got != DefaultQuestionFilter {
+		t.Errorf("question filter %q on dry run, want default", got)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Error("config was saved on dry run")
+	}
+	actions := so.DryRun.Actions()
+	if len(actions) != 1 || actions[0].Action != "create filter" || actions[0].Text != name {
+		t.Errorf("actions %+v, want create filter %s", actions, name)
+	}
+
+	// Filter is created once and stored to config file
+	so = newSlackOverflow()
+	so.QuestionFilter(newTestWorker())
+	so.QuestionFilter(newTestWorker())
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("%d requests made, want 1", n)
+	}
+	if got := so.StackExchange.GetQuestionFilter(); got != "!test" {
+		t.Errorf("question filter %q, want created filter", got)
+	}
+	if got := so.Config.StackExchange.Filters[name]; got != "!test" {
+		t.Errorf("filter %s in config is %q, want created filter", name, got)
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("config was not saved: %s", err.Error())
+	}
+
+	// Stored filter is used without request
+	so = newSlackOverflow()
+	so.Config.StackExchange.SetFilter(name, "!stored")
+	so.QuestionFilter(newTestWorker())
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("%d requests made with stored filter, want 1", n)
+	}
+	if got := so.StackExchange.GetQuestionFilter(); got != "!stored" {
+		t.Errorf("question filter %q, want stored filter", got)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package fakese
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+)
+
+const (
+	firstQuestionID = 46000000
+	firstAnswerID   = 47000000
+	firstCommentID  = 80000000
+	firstUserID     = 1000
+	numUsers        = 20
+)
+
+// Comment returned by questions/{ids}/comments
+type Comment struct {
+	CID          int                     `json:"comment_id"`
+	PostID       int                     `json:"post_id"`
+	Owner        internal.ShallowUserObj `json:"owner"`
+	Score        int                     `json:"score"`
+	CreationDate int64                   `json:"creation_date"`
+	Body         string                  `json:"body"`
+}
+
+// dataset is in-memory content of fake site, questions are ordered by
+// creation date
+type dataset struct {
+	rnd       *rand.Rand
+	site      string
+	tags      []string
+	users     []internal.UserObj
+	questions []internal.QuestionObj
+	answers   map[int][]internal.AnswerObj
+	comments  map[int][]Comment
+	revisions map[int][]internal.RevisionObj
+	nextQID   int
+	nextAID   int
+	nextCID   int
+}
+
+// newDataset generates questions created during day before now
+func newDataset(seed int64, site string, tags []string, questions int, now time.Time) *dataset {
+	d := &dataset{
+		rnd:       rand.New(rand.NewSource(seed)),
+		site:      site,
+		tags:      tags,
+		answers:   make(map[int][]internal.AnswerObj),
+		comments:  make(map[int][]Comment),
+		revisions: make(map[int][]internal.RevisionObj),
+		nextQID:   firstQuestionID,
+		nextAID:   firstAnswerID,
+		nextCID:   firstCommentID,
+	}
+	for i := 0; i < numUsers; i++ {
+		UID := firstUserID + i
+		d.users = append(d.users, internal.UserObj{
+			UID:          UID,
+			DisplayName:  fmt.Sprintf("user%d", UID),
+			Link:         fmt.Sprintf("https://%s.com/users/%d", site, UID),
+			Reputation:   1 + d.rnd.Intn(50000),
+			AcceptRate:   d.rnd.Intn(100),
+			CreationDate: now.Add(-time.Duration(d.rnd.Intn(1000)) * 24 * time.Hour).Unix(),
+		})
+	}
+	for i := 0; i < questions; i++ {
+		// Spread over the day, oldest first
+		created := now.Add(-24 * time.Hour).Add(time.Duration(i) * 24 * time.Hour / time.Duration(questions))
+		d.addQuestion(created)
+	}
+	return d
+}
+
+// addQuestion generates question with answers and comments
+func (d *dataset) addQuestion(created time.Time) internal.QuestionObj {
+	QID := d.nextQID
+	d.nextQID++
+	tags := []string{d.tags[d.rnd.Intn(len(d.tags))]}
+	if d.rnd.Intn(3) == 0 {
+		tags = append(tags, fakeTags[d.rnd.Intn(len(fakeTags))])
+	}
+	q := internal.QuestionObj{
+		QID:              QID,
+		Title:            fmt.Sprintf("How to %s with %s?", fakeTasks[d.rnd.Intn(len(fakeTasks))], strings.Join(tags, " and ")),
+		CreationDate:     created.Unix(),
+		LastActivityDate: created.Unix(),
+		Owner:            d.shallowUser(d.randomUser()),
+		ShareLink:        fmt.Sprintf("https://%s.com/q/%d", d.site, QID),
+		Tags:             tags,
+		Score:            d.rnd.Intn(5) - 1,
+		ViewCount:        1 + d.rnd.Intn(200),
+		BodyMarkdown:     "I tried the docs but could not get it working.",
+	}
+	for i := d.rnd.Intn(4); i > 0; i-- {
+		d.addAnswer(&q, created.Add(time.Duration(1+d.rnd.Intn(60))*time.Minute))
+	}
+	for i := d.rnd.Intn(3); i > 0; i-- {
+		d.addComment(&q, created.Add(time.Duration(1+d.rnd.Intn(60))*time.Minute))
+	}
+	d.questions = append(d.questions, q)
+	return q
+}
+
+func (d *dataset) addAnswer(q *internal.QuestionObj, created time.Time) {
+	a := internal.AnswerObj{
+		AID:              d.nextAID,
+		QID:              q.QID,
+		Owner:            d.shallowUser(d.randomUser()),
+		IsAccepted:       len(d.answers[q.QID]) == 0 && d.rnd.Intn(3) == 0,
+		Score:            d.rnd.Intn(10),
+		CreationDate:     created.Unix(),
+		LastActivityDate: created.Unix(),
+	}
+	d.nextAID++
+	d.answers[q.QID] = append(d.answers[q.QID], a)
+	q.AnswerCount++
+	q.IsAnswered = q.IsAnswered || a.IsAccepted || a.Score > 0
+	if a.CreationDate > q.LastActivityDate {
+		q.LastActivityDate = a.CreationDate
+	}
+}
+
+func (d *dataset) addComment(q *internal.QuestionObj, created time.Time) {
+	d.comments[q.QID] = append(d.comments[q.QID], Comment{
+		CID:          d.nextCID,
+		PostID:       q.QID,
+		Owner:        d.shallowUser(d.randomUser()),
+		CreationDate: created.Unix(),
+		Body:         "Please share what you have tried.",
+	})
+	d.nextCID++
+	q.CommentCount++
+}
+
+func (d *dataset) randomUser() internal.UserObj {
+	return d.users[d.rnd.Intn(len(d.users))]
+}
+
+func (d *dataset) shallowUser(u internal.UserObj) internal.ShallowUserObj {
+	return internal.ShallowUserObj{
+		UID:         u.UID,
+		DisplayName: u.DisplayName,
+		Link:        u.Link,
+		Reputation:  u.Reputation,
+		AcceptRate:  u.AcceptRate,
+	}
+}
+
+func (d *dataset) question(QID int) (*internal.QuestionObj, bool) {
+	for i := range d.questions {
+		if d.questions[i].QID == QID {
+			return &d.questions[i], true
+		}
+	}
+	return nil, false
+}
+
+func (d *dataset) user(UID int) (internal.UserObj, bool) {
+	for _, u := range d.users {
+		if u.UID == UID {
+			return u, true
+		}
+	}
+	return internal.UserObj{}, false
+}
+
+// topAnswerers of tag by answer score
+func (d *dataset) topAnswerers(tag string) []internal.TagScoreObj {
+	scores := make(map[int]*internal.TagScoreObj)
+	var order []int
+	for _, q := range d.questions {
+		if !q.HasAnyTag([]string{tag}) {
+			continue
+		}
+		for _, a := range d.answers[q.QID] {
+			s, ok := scores[a.Owner.UID]
+			if !ok {
+				s = &internal.TagScoreObj{User: a.Owner}
+				scores[a.Owner.UID] = s
+				order = append(order, a.Owner.UID)
+			}
+			s.PostCount++
+			s.Score += a.Score
+		}
+	}
+	var top []internal.TagScoreObj
+	for _, UID := range order {
+		top = append(top, *scores[UID])
+	}
+	return top
+}
+
+var (
+	fakeTasks = []string{
+		"load a model", "animate a camera", "register a component",
+		"handle click events", "add shadows", "use a custom shader",
+		"preload assets", "enable VR mode", "update an entity position",
+	}
+	fakeTags = []string{"javascript", "three.js", "webvr", "html", "webgl"}
+)
@@ -0,0 +1,446 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+// Package fakese is in-process fake of Stack Exchange API serving seedable
+// in-memory dataset. Point stackexchange.api-host at URL of the server to
+// develop and test slackoverflow without network access.
+package fakese
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkungla/slackoverflow/cmd/slackoverflow/internal"
+)
+
+const (
+	defaultQuotaMax  = 10000
+	defaultPageSize  = 30
+	maxPageSize      = 100
+	defaultQuestions = 50
+)
+
+// Options of fake Stack Exchange API
+type Options struct {
+	// Seed of generated dataset, same seed generates same dataset
+	Seed int64
+	// Site served e.g. stackoverflow
+	Site string
+	// Tags of generated questions
+	Tags []string
+	// Questions generated during day before server was created
+	Questions int
+	// QuotaMax is daily quota, throttle_violation is returned when it is used
+	QuotaMax int
+	// BackoffEvery adds Backoff seconds to every Nth response
+	BackoffEvery int
+	Backoff      int
+}
+
+// New returns fake Stack Exchange API, use Start to serve it on random
+// local port or serve it as http.Handler.
+func New(opts Options) *Server {
+	if opts.Site == "" {
+		opts.Site = "stackoverflow"
+	}
+	if len(opts.Tags) == 0 {
+		opts.Tags = []string{"aframe"}
+	}
+	if opts.Questions <= 0 {
+		opts.Questions = defaultQuestions
+	}
+	if opts.QuotaMax <= 0 {
+		opts.QuotaMax = defaultQuotaMax
+	}
+	return &Server{
+		opts:           opts,
+		data:           newDataset(opts.Seed, opts.Site, opts.Tags, opts.Questions, time.Now().UTC()),
+		quotaRemaining: opts.QuotaMax,
+	}
+}
+
+// Server is fake Stack Exchange API
+type Server struct {
+	opts   Options
+	server *httptest.Server
+
+	mu             sync.Mutex
+	data           *dataset
+	quotaRemaining int
+	requests       int
+	paths          []string
+	nextBackoff    int
+	failures       []failure
+}
+
+// failure is injected error response
+type failure struct {
+	status int
+	body   string
+}
+
+// Start serving on random local port
+func (s *Server) Start() {
+	s.server = httptest.NewServer(s)
+}
+
+// URL to use as stackexchange.api-host
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close the server
+func (s *Server) Close() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+// AddQuestion creates new question with current time as creation date
+func (s *Server) AddQuestion() internal.QuestionObj {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data.addQuestion(time.Now().UTC())
+}
+
+// AddAnswer adds answer to question, returns false if question does not exist
+func (s *Server) AddAnswer(QID int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, ok := s.data.question(QID)
+	if ok {
+		s.data.addAnswer(q, time.Now().UTC())
+	}
+	return ok
+}
+
+// EditQuestion modifies question, title and tags changes are recorded as
+// revision. Returns false if question does not exist.
+func (s *Server) EditQuestion(QID int, edit func(q *internal.QuestionObj)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, ok := s.data.question(QID)
+	if !ok {
+		return false
+	}
+	before := *q
+	before.Tags = append([]string(nil), q.Tags...)
+	edit(q)
+	now := time.Now().UTC().Unix()
+	q.LastActivityDate = now
+	rev := internal.RevisionObj{
+		PostID:         QID,
+		RevisionNumber: len(s.data.revisions[QID]) + 2,
+		RevisionType:   "single_user",
+		CreationDate:   now,
+		User:           s.data.shallowUser(s.data.randomUser()),
+	}
+	if q.Title != before.Title {
+		rev.Title, rev.LastTitle = q.Title, before.Title
+	}
+	if !reflect.DeepEqual(q.Tags, before.Tags) {
+		rev.Tags, rev.LastTags = q.Tags, before.Tags
+	}
+	if rev.Title != "" || len(rev.Tags) > 0 {
+		s.data.revisions[QID] = append(s.data.revisions[QID], rev)
+	}
+	return true
+}
+
+// FailNext makes next request fail with API error e.g.
+// FailNext(502, "throttle_violation", "too many requests")
+func (s *Server) FailNext(errorID int, name string, message string) {
+	body, _ := json.Marshal(errorWrapper{ErrorID: errorID, ErrorName: name, ErrorMessage: message})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{status: http.StatusBadRequest, body: string(body)})
+}
+
+// FailNextHTTP makes next request fail with status and body which is not
+// API error e.g. HTML page of proxy
+func (s *Server) FailNextHTTP(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{status: status, body: body})
+}
+
+// BackoffNext adds backoff to next response
+func (s *Server) BackoffNext(seconds int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextBackoff = seconds
+}
+
+// QuotaRemaining returns quota left
+func (s *Server) QuotaRemaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.quotaRemaining
+}
+
+// Requests returns paths of requests served so far
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.paths...)
+}
+
+// errorWrapper is body of API error response
+type errorWrapper struct {
+	ErrorID      int    `json:"error_id"`
+	ErrorName    string `json:"error_name"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// wrapper is common wrapper of API responses
+type wrapper struct {
+	Backoff        int         `json:"backoff,omitempty"`
+	HasMore        bool        `json:"has_more"`
+	Page           int         `json:"page"`
+	PageSize       int         `json:"page_size"`
+	QuotaMax       int         `json:"quota_max"`
+	QuotaRemaining int         `json:"quota_remaining"`
+	Total          int         `json:"total"`
+	Items          interface{} `json:"items"`
+}
+
+// ServeHTTP serves API requests, path is /{version}/{method}
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	s.paths = append(s.paths, r.URL.Path)
+
+	if len(s.failures) > 0 {
+		f := s.failures[0]
+		s.failures = s.failures[1:]
+		s.write(w, r, f.status, []byte(f.body))
+		return
+	}
+	if s.quotaRemaining <= 0 {
+		s.apiError(w, r, 502, "throttle_violation", "too many requests from this IP, more requests available tomorrow")
+		return
+	}
+	s.quotaRemaining--
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 {
+		s.apiError(w, r, 404, "no_method", "no method found with this name")
+		return
+	}
+	query := r.URL.Query()
+	if site := query.Get("site"); site != "" && site != s.opts.Site {
+		s.apiError(w, r, 400, "bad_parameter", "site is required")
+		return
+	}
+	items, ok := s.route(parts[1:], query)
+	if !ok {
+		s.apiError(w, r, 404, "no_method", "no method found with this name")
+		return
+	}
+	s.page(w, r, items)
+}
+
+// route returns all items of method before paging
+func (s *Server) route(method []string, query url.Values) (interface{}, bool) {
+	switch {
+	case len(method) == 2 && method[0] == "search" && method[1] == "advanced":
+		return s.searchAdvanced(query), true
+	case len(method) == 2 && method[0] == "questions":
+		var items []internal.QuestionObj
+		for _, QID := range ids(method[1]) {
+			if q, ok := s.data.question(QID); ok {
+				items = append(items, *q)
+			}
+		}
+		return items, true
+	case len(method) == 3 && method[0] == "questions" && method[2] == "answers":
+		var items []internal.AnswerObj
+		for _, QID := range ids(method[1]) {
+			items = append(items, s.data.answers[QID]...)
+		}
+		return items, true
+	case len(method) == 3 && method[0] == "questions" && method[2] == "comments":
+		var items []Comment
+		for _, QID := range ids(method[1]) {
+			items = append(items, s.data.comments[QID]...)
+		}
+		return items, true
+	case len(method) == 3 && method[0] == "posts" && method[2] == "revisions":
+		var items []internal.RevisionObj
+		for _, QID := range ids(method[1]) {
+			items = append(items, s.data.revisions[QID]...)
+		}
+		return items, true
+	case len(method) == 2 && method[0] == "users":
+		var items []internal.UserObj
+		for _, UID := range ids(method[1]) {
+			if u, ok := s.data.user(UID); ok {
+				items = append(items, u)
+			}
+		}
+		return items, true
+	case len(method) == 4 && method[0] == "tags" && method[2] == "top-answerers":
+		return s.data.topAnswerers(method[1]), true
+	case len(method) == 3 && method[0] == "tags" && method[2] == "info":
+		var items []internal.TagObj
+		for _, tag := range strings.Split(method[1], ";") {
+			count := 0
+			for _, q := range s.data.questions {
+				if q.HasAnyTag([]string{tag}) {
+					count++
+				}
+			}
+			if count > 0 {
+				items = append(items, internal.TagObj{Name: tag, Count: count})
+			}
+		}
+		return items, true
+	case len(method) == 2 && method[0] == "filters" && method[1] == "create":
+		return []internal.FilterObj{{Filter: "fakese", FilterType: "safe"}}, true
+	}
+	return nil, false
+}
+
+// searchAdvanced filters questions by tagged, fromdate and todate and
+// sorts them by creation, activity or votes
+func (s *Server) searchAdvanced(query url.Values) []internal.QuestionObj {
+	var tagged []string
+	if query.Get("tagged") != "" {
+		tagged = strings.Split(query.Get("tagged"), ";")
+	}
+	fromDate, _ := strconv.ParseInt(query.Get("fromdate"), 10, 64)
+	toDate, _ := strconv.ParseInt(query.Get("todate"), 10, 64)
+
+	var items []internal.QuestionObj
+	for _, q := range s.data.questions {
+		if len(tagged) > 0 && !q.HasAnyTag(tagged) {
+			continue
+		}
+		if fromDate > 0 && q.CreationDate < fromDate {
+			continue
+		}
+		if toDate > 0 && q.CreationDate > toDate {
+			continue
+		}
+		items = append(items, q)
+	}
+	key := func(q internal.QuestionObj) int64 {
+		switch query.Get("sort") {
+		case "activity":
+			return q.LastActivityDate
+		case "votes":
+			return int64(q.Score)
+		}
+		return q.CreationDate
+	}
+	desc := query.Get("order") != "asc"
+	sort.SliceStable(items, func(i, j int) bool {
+		if desc {
+			return key(items[i]) > key(items[j])
+		}
+		return key(items[i]) < key(items[j])
+	})
+	return items
+}
+
+// page writes requested page of items
+func (s *Server) page(w http.ResponseWriter, r *http.Request, items interface{}) {
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(query.Get("pagesize"))
+	if err != nil {
+		pageSize = defaultPageSize
+	}
+	if pageSize < 0 || pageSize > maxPageSize {
+		s.apiError(w, r, 400, "bad_parameter", "pagesize")
+		return
+	}
+	all := reflect.ValueOf(items)
+	total := 0
+	if all.IsValid() {
+		total = all.Len()
+	}
+	from, to := (page-1)*pageSize, page*pageSize
+	if from > total {
+		from = total
+	}
+	if to > total {
+		to = total
+	}
+	pageItems := []interface{}{}
+	for i := from; i < to; i++ {
+		pageItems = append(pageItems, all.Index(i).Interface())
+	}
+	resp := wrapper{
+		HasMore:        to < total,
+		Page:           page,
+		PageSize:       pageSize,
+		QuotaMax:       s.opts.QuotaMax,
+		QuotaRemaining: s.quotaRemaining,
+		Total:          total,
+		Items:          pageItems,
+	}
+	if s.nextBackoff > 0 {
+		resp.Backoff, s.nextBackoff = s.nextBackoff, 0
+	} else if s.opts.BackoffEvery > 0 && s.requests%s.opts.BackoffEvery == 0 {
+		resp.Backoff = s.opts.Backoff
+	}
+	body, _ := json.Marshal(resp)
+	s.write(w, r, http.StatusOK, body)
+}
+
+func (s *Server) apiError(w http.ResponseWriter, r *http.Request, id int, name string, message string) {
+	body, _ := json.Marshal(errorWrapper{ErrorID: id, ErrorName: name, ErrorMessage: message})
+	s.write(w, r, http.StatusBadRequest, body)
+}
+
+// write response, compressed like API does if client accepts gzip
+func (s *Server) write(w http.ResponseWriter, r *http.Request, status int, body []byte) {
+	if strings.HasPrefix(string(body), "{") {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.WriteHeader(status)
+		w.Write(body)
+		return
+	}
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(status)
+	gz := gzip.NewWriter(w)
+	gz.Write(body)
+	gz.Close()
+}
+
+// ids parses semicolon delimited ids
+func ids(list string) []int {
+	var parsed []int
+	for _, id := range strings.Split(list, ";") {
+		if n, err := strconv.Atoi(id); err == nil {
+			parsed = append(parsed, n)
+		}
+	}
+	return parsed
+}
+
+// String describes dataset
+func (s *Server) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("%d questions tagged %s on %s, %d users",
+		len(s.data.questions), strings.Join(s.opts.Tags, ";"), s.opts.Site, len(s.data.users))
+}
@@ -0,0 +1,332 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+// Package fakeslack is in-process fake of Slack Web API recording all calls.
+// Point slack.api-host at URL of the server to develop and test
+// slackoverflow without posting to real workspace.
+package fakeslack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Call is recorded Web API call
+type Call struct {
+	// Method e.g. chat.postMessage
+	Method string
+	// Params of form or query without token
+	Params url.Values
+	// Token used for the call
+	Token string
+}
+
+// Message posted to fake Slack
+type Message struct {
+	Channel     string
+	TS          string
+	Text        string
+	Attachments string
+	Updated     int
+	Deleted     bool
+	Reactions   []Reaction
+}
+
+// Reaction on message
+type Reaction struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Users []string `json:"users"`
+}
+
+// Channel of fake team
+type Channel struct {
+	ID   string
+	Name string
+}
+
+// New returns fake Slack API with #general channel, token is required
+// from all calls unless empty.
+func New(token string) *Server {
+	return &Server{
+		token:    token,
+		channels: []Channel{{ID: "C0000000001", Name: "general"}},
+		messages: make(map[string]*Message),
+		ts:       time.Now().Unix(),
+	}
+}
+
+// Server is fake Slack Web API
+type Server struct {
+	token  string
+	server *httptest.Server
+
+	mu       sync.Mutex
+	calls    []Call
+	channels []Channel
+	messages map[string]*Message
+	ts       int64
+	seq      int
+	failures map[string]string
+}
+
+// Start serving on random local port
+func (s *Server) Start() {
+	s.server = httptest.NewServer(s)
+}
+
+// URL to use as slack.api-host
+func (s *Server) URL() string {
+	return s.server.URL + "/api"
+}
+
+// Close the server
+func (s *Server) Close() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+// AddChannel to team, returns its id
+func (s *Server) AddChannel(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := fmt.Sprintf("C%010d", len(s.channels)+1)
+	s.channels = append(s.channels, Channel{ID: id, Name: name})
+	return id
+}
+
+// AddReaction to message posted in channel at ts
+func (s *Server) AddReaction(channel string, ts string, name string, user string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.messages[channel+"/"+ts]
+	if !ok {
+		return false
+	}
+	for i := range m.Reactions {
+		if m.Reactions[i].Name == name {
+			m.Reactions[i].Count++
+			m.Reactions[i].Users = append(m.Reactions[i].Users, user)
+			return true
+		}
+	}
+	m.Reactions = append(m.Reactions, Reaction{Name: name, Count: 1, Users: []string{user}})
+	return true
+}
+
+// FailNext makes next call of method fail with Slack error e.g.
+// FailNext("chat.postMessage", "channel_not_found")
+func (s *Server) FailNext(method string, slackErr string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures == nil {
+		s.failures = make(map[string]string)
+	}
+	s.failures[method] = slackErr
+}
+
+// Calls returns calls made so far, optionally only calls of given methods
+func (s *Server) Calls(methods ...string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var calls []Call
+	for _, c := range s.calls {
+		if len(methods) == 0 || contains(methods, c.Method) {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Messages returns messages of channel in posting order
+func (s *Server) Messages(channel string) []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var messages []Message
+	for _, m := range s.messages {
+		if m.Channel == channel {
+			messages = append(messages, *m)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].TS < messages[j].TS
+	})
+	return messages
+}
+
+// ServeHTTP serves Web API calls, path is /api/{method}
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	params, token, err := readParams(r)
+	if err != nil {
+		s.write(w, map[string]interface{}{"ok": false, "error": "invalid_form_data"})
+		return
+	}
+	method := strings.TrimPrefix(r.URL.Path[strings.LastIndex(r.URL.Path, "/"):], "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, Call{Method: method, Params: params, Token: token})
+
+	if s.token != "" && token != s.token {
+		s.write(w, map[string]interface{}{"ok": false, "error": "invalid_auth"})
+		return
+	}
+	if slackErr, ok := s.failures[method]; ok {
+		delete(s.failures, method)
+		s.write(w, map[string]interface{}{"ok": false, "error": slackErr})
+		return
+	}
+	resp, slackErr := s.call(method, params)
+	if slackErr != "" {
+		s.write(w, map[string]interface{}{"ok": false, "error": slackErr})
+		return
+	}
+	resp["ok"] = true
+	s.write(w, resp)
+}
+
+// call handles method, returns response or Slack error
+func (s *Server) call(method string, params url.Values) (map[string]interface{}, string) {
+	switch method {
+	case "auth.test":
+		return map[string]interface{}{
+			"url": "https://fake.slack.com/", "team": "Fake", "user": "slackoverflow",
+			"team_id": "T0000000001", "user_id": "U0000000001",
+		}, ""
+	case "team.info":
+		return map[string]interface{}{"team": map[string]interface{}{
+			"id": "T0000000001", "name": "Fake", "domain": "fake", "email_domain": "",
+			"icon": map[string]interface{}{},
+		}}, ""
+	case "channels.list", "conversations.list":
+		var channels []map[string]interface{}
+		for _, c := range s.channels {
+			channels = append(channels, map[string]interface{}{
+				"id": c.ID, "name": c.Name, "is_channel": true, "is_member": true, "created": s.ts,
+			})
+		}
+		return map[string]interface{}{"channels": channels}, ""
+	case "im.open", "conversations.open":
+		return map[string]interface{}{"channel": map[string]interface{}{"id": "D" + params.Get("user")}}, ""
+	case "chat.postMessage":
+		channel := s.channelID(params.Get("channel"))
+		if channel == "" {
+			return nil, "channel_not_found"
+		}
+		s.seq++
+		m := &Message{
+			Channel:     channel,
+			TS:          fmt.Sprintf("%d.%06d", s.ts, s.seq),
+			Text:        params.Get("text"),
+			Attachments: params.Get("attachments"),
+		}
+		s.messages[channel+"/"+m.TS] = m
+		return map[string]interface{}{"channel": channel, "ts": m.TS, "message": s.messageJSON(m)}, ""
+	case "chat.update", "chat.delete":
+		channel := s.channelID(params.Get("channel"))
+		m, ok := s.messages[channel+"/"+params.Get("ts")]
+		if !ok || m.Deleted {
+			return nil, "message_not_found"
+		}
+		if method == "chat.delete" {
+			m.Deleted = true
+		} else {
+			m.Text = params.Get("text")
+			m.Attachments = params.Get("attachments")
+			m.Updated++
+		}
+		return map[string]interface{}{"channel": channel, "ts": m.TS, "text": m.Text}, ""
+	case "reactions.get":
+		channel := s.channelID(params.Get("channel"))
+		m, ok := s.messages[channel+"/"+params.Get("timestamp")]
+		if !ok {
+			return nil, "message_not_found"
+		}
+		return map[string]interface{}{"type": "message", "channel": channel, "message": s.messageJSON(m)}, ""
+	}
+	return nil, "unknown_method"
+}
+
+// channelID resolves channel given by id or #name
+func (s *Server) channelID(channel string) string {
+	name := strings.TrimPrefix(channel, "#")
+	for _, c := range s.channels {
+		if c.ID == channel || c.Name == name {
+			return c.ID
+		}
+	}
+	if strings.HasPrefix(channel, "D") {
+		return channel
+	}
+	return ""
+}
+
+func (s *Server) messageJSON(m *Message) map[string]interface{} {
+	msg := map[string]interface{}{"type": "message", "ts": m.TS, "text": m.Text}
+	var attachments []interface{}
+	if json.Unmarshal([]byte(m.Attachments), &attachments) == nil {
+		msg["attachments"] = attachments
+	}
+	if len(m.Reactions) > 0 {
+		msg["reactions"] = m.Reactions
+	}
+	return msg
+}
+
+func (s *Server) write(w http.ResponseWriter, resp map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// readParams returns form or JSON parameters and token of call
+func readParams(r *http.Request) (url.Values, string, error) {
+	params := url.Values{}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, "", err
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, "", err
+		}
+		for k, v := range fields {
+			if s, ok := v.(string); ok {
+				params.Set(k, s)
+				continue
+			}
+			encoded, _ := json.Marshal(v)
+			params.Set(k, string(encoded))
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return nil, "", err
+		}
+		params = r.Form
+	}
+	token := params.Get("token")
+	params.Del("token")
+	if auth := r.Header.Get("Authorization"); token == "" && strings.HasPrefix(auth, "Bearer ") {
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return params, token, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
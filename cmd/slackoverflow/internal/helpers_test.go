@@ -0,0 +1,15 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/std/log"
+)
+
+// newTestWorker returns worker logging only errors
+func newTestWorker() *cli.Worker {
+	return &cli.Worker{Log: log.NewStdout(log.ERROR)}
+}
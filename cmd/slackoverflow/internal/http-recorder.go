@@ -0,0 +1,362 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/howi-ce/howi/std/errors"
+)
+
+// redacted replaces secrets in recorded requests and responses
+const redacted = "REDACTED"
+
+var (
+	// secretParams are query and form parameters never written to recordings
+	secretParams = []string{"key", "access_token", "token", "client_secret", "code"}
+	// secretHeaders are request and response headers never written to recordings
+	secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+	// secretFields are fields of JSON responses never written to recordings
+	secretFields = []string{"access_token", "refresh_token", "token", "client_secret"}
+	// unsafeFileChars are replaced in names of recording files
+	unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9.]+`)
+)
+
+// recordedExchange is request and response pair stored as JSON file
+type recordedExchange struct {
+	Method        string      `json:"method"`
+	URL           string      `json:"url"`
+	RequestHeader http.Header `json:"request_header,omitempty"`
+	RequestBody   string      `json:"request_body,omitempty"`
+	StatusCode    int         `json:"status_code"`
+	Header        http.Header `json:"header"`
+	Body          string      `json:"body"`
+}
+
+// NewRecordingTransport returns transport which saves every request and
+// response to dir with secrets redacted. Requests are made with next,
+// NewHTTPTransport is used if next is nil.
+func NewRecordingTransport(dir string, next http.RoundTripper) (*RecordingTransport, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	if next == nil {
+		next = NewHTTPTransport()
+	}
+	return &RecordingTransport{dir: dir, next: next}, nil
+}
+
+// RecordingTransport records request/response pairs for ReplayTransport
+type RecordingTransport struct {
+	dir  string
+	next http.RoundTripper
+	mu   sync.Mutex
+	seq  int
+}
+
+// RoundTrip makes request and records it with response
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	// Bodies are stored decompressed, readBody handles both
+	body, err := readBody(resp)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = int64(len(body))
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	exchange := recordedExchange{
+		Method:        req.Method,
+		URL:           redactURL(req.URL),
+		RequestHeader: redactHeader(req.Header),
+		RequestBody:   redactBody(req.Header.Get("Content-Type"), reqBody),
+		StatusCode:    resp.StatusCode,
+		Header:        redactHeader(resp.Header),
+		Body:          redactResponse(resp.Header.Get("Content-Type"), body),
+	}
+	if err := t.save(req.URL, exchange); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *RecordingTransport) save(u *url.URL, exchange recordedExchange) error {
+	data, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq++
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(u.Host+u.Path, "-"), "-")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	file := filepath.Join(t.dir, fmt.Sprintf("%04d-%s-%s.json", t.seq, exchange.Method, name))
+	return ioutil.WriteFile(file, data, 0600)
+}
+
+// NewReplayTransport returns transport serving responses recorded to dir
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, errors.Newf("no recorded requests in %s", dir)
+	}
+	sort.Strings(files)
+	t := &ReplayTransport{}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		exchange := recordedExchange{}
+		if err := json.Unmarshal(data, &exchange); err != nil {
+			return nil, errors.Newf("%s: %s", file, err.Error())
+		}
+		t.exchanges = append(t.exchanges, exchange)
+	}
+	t.used = make([]bool, len(t.exchanges))
+	return t, nil
+}
+
+// ReplayTransport serves recorded responses in recorded order. Request is
+// matched by method and URL, requests with dates in query e.g. fromdate
+// fall back to unused recording of same endpoint with most equal parameters.
+type ReplayTransport struct {
+	mu        sync.Mutex
+	exchanges []recordedExchange
+	used      []bool
+}
+
+// RoundTrip returns recorded response of request
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	reqURL := redactURL(req.URL)
+	endpoint, query := splitURL(reqURL)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	match, best := -1, -1
+	for i, exchange := range t.exchanges {
+		if t.used[i] || exchange.Method != req.Method {
+			continue
+		}
+		if exchange.URL == reqURL {
+			match = i
+			break
+		}
+		recEndpoint, recQuery := splitURL(exchange.URL)
+		if recEndpoint != endpoint {
+			continue
+		}
+		if score := matchingParams(query, recQuery); score > best {
+			match, best = i, score
+		}
+	}
+	if match == -1 {
+		return nil, errors.Newf("no recorded response for %s %s", req.Method, reqURL)
+	}
+	t.used[match] = true
+	exchange := t.exchanges[match]
+	header := http.Header{}
+	for k, v := range exchange.Header {
+		header[k] = v
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.StatusCode, http.StatusText(exchange.StatusCode)),
+		StatusCode:    exchange.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(exchange.Body)),
+		ContentLength: int64(len(exchange.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Remaining returns number of recorded responses not served yet
+func (t *ReplayTransport) Remaining() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	remaining := 0
+	for _, used := range t.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// splitURL returns URL without query and query values
+func splitURL(rawURL string) (string, url.Values) {
+	parts := strings.SplitN(rawURL, "?", 2)
+	if len(parts) == 1 {
+		return parts[0], url.Values{}
+	}
+	query, _ := url.ParseQuery(parts[1])
+	return parts[0], query
+}
+
+// matchingParams returns number of parameters with equal values
+func matchingParams(a url.Values, b url.Values) int {
+	matching := 0
+	for param := range a {
+		if a.Get(param) == b.Get(param) {
+			matching++
+		}
+	}
+	return matching
+}
+
+// redactURL returns URL with secret query parameters replaced and
+// parameters sorted
+func redactURL(u *url.URL) string {
+	redactedURL := *u
+	query := u.Query()
+	redactValues(query)
+	redactedURL.RawQuery = query.Encode()
+	return redactedURL.String()
+}
+
+func redactHeader(header http.Header) http.Header {
+	redactedHeader := http.Header{}
+	for k, v := range header {
+		redactedHeader[k] = v
+	}
+	for _, name := range secretHeaders {
+		if redactedHeader.Get(name) != "" {
+			redactedHeader.Set(name, redacted)
+		}
+	}
+	return redactedHeader
+}
+
+// redactBody redacts secrets of form encoded request body
+func redactBody(contentType string, body []byte) string {
+	if !strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return string(body)
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return redacted
+	}
+	redactValues(values)
+	return values.Encode()
+}
+
+// redactResponse redacts secret fields of JSON or form encoded response
+// body, body is kept as is if it has no secrets
+func redactResponse(contentType string, body []byte) string {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		return redactBody(contentType, body)
+	}
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil || !redactJSON(v) {
+		return string(body)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return redacted
+	}
+	return string(data)
+}
+
+// redactJSON replaces secret fields of decoded JSON value in place and
+// returns true if any was replaced
+func redactJSON(v interface{}) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if isSecretField(k) {
+				if field != nil && field != "" {
+					v[k] = redacted
+					found = true
+				}
+				continue
+			}
+			if redactJSON(field) {
+				found = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactJSON(item) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+func isSecretField(name string) bool {
+	for _, field := range secretFields {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// redactParam returns value of query parameter or redacted if it is secret
+func redactParam(param string, value string) string {
+	for _, secret := range secretParams {
+		if param == secret && value != "" {
+			return redacted
+		}
+	}
+	return value
+}
+
+// redactRawURL returns URL with secret query parameters replaced
+func redactRawURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+	return redactURL(u)
+}
+
+func redactValues(values url.Values) {
+	for _, param := range secretParams {
+		if values.Get(param) != "" {
+			values.Set(param, redacted)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordingTransportRedactsResponses(t *testing.T) {
+	const secretToken = "secret-access-token"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/access_token/json" {
+			http.NotFound(w, r)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret-session"})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"` + secretToken + `","expires":86400}`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "slackoverflow-recording-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	recorder, err := NewRecordingTransport(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oauth := &StackExchangeOAuth{
+		ClientID:     "1",
+		ClientSecret: "secret-client",
+		RedirectURL:  "http://localhost/",
+		Host:         server.URL,
+		Transport:    recorder,
+	}
+	token, err := oauth.exchange("secret-code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != secretToken {
+		t.Errorf("access token %q, want %q", token.AccessToken, secretToken)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("%d requests recorded, want 1", len(files))
+	}
+	data, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{secretToken, "secret-session", "secret-client", "secret-code"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("secret %q is not redacted:\n%s", secret, data)
+		}
+	}
+	if !bytes.Contains(data, []byte(`\"expires\":86400`)) {
+		t.Errorf("fields other than secrets must be kept:\n%s", data)
+	}
+}
+
+func TestRedactRawURL(t *testing.T) {
+	got := redactRawURL("https://api.stackexchange.com/2.2/search/advanced?site=stackoverflow&key=k3y&access_token=t0ken")
+	want := "https://api.stackexchange.com/2.2/search/advanced?access_token=REDACTED&key=REDACTED&site=stackoverflow"
+	if got != want {
+		t.Errorf("redactRawURL = %q, want %q", got, want)
+	}
+	if got := redactParam("access_token", "t0ken"); got != redacted {
+		t.Errorf("access_token shown as %q", got)
+	}
+	if got := redactParam("key", ""); got != "" {
+		t.Errorf("empty key shown as %q, want empty", got)
+	}
+	if got := redactParam("site", "stackoverflow"); got != "stackoverflow" {
+		t.Errorf("site shown as %q", got)
+	}
+}
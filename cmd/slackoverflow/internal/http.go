@@ -5,79 +5,249 @@
 package internal
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
-// HTTPGetByURL make Get request
-func HTTPGetByURL(url string, collection interface{}) error {
+const (
+	httpConnectTimeout = 10 * time.Second
+	// httpReadTimeout is maximum time of waiting response headers
+	httpReadTimeout = 30 * time.Second
+	// httpRequestTimeout is maximum time of single request including body
+	httpRequestTimeout = time.Minute
+	httpMaxRetries     = 3
+	httpMinBackoff     = time.Second
+	httpMaxBackoff     = 30 * time.Second
+	// httpMaxErrorBody is maximum length of response body in error message
+	httpMaxErrorBody = 512
+)
 
-	client := &http.Client{
-		Transport: http.DefaultTransport,
-	}
+// UserAgent sent with all HTTP requests
+func UserAgent() string {
+	return fmt.Sprintf("%s/%s (+https://github.com/mkungla/slackoverflow)", Name, Version)
+}
 
-	response, err := client.Get(url)
+// NewHTTPClient returns HTTP client with timeouts which honors
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+func NewHTTPClient() *HTTPClient {
+	transport := NewHTTPTransport()
+	return &HTTPClient{
+		transport:  transport,
+		client:     &http.Client{Transport: transport, Timeout: httpRequestTimeout},
+		UserAgent:  UserAgent(),
+		MaxRetries: httpMaxRetries,
+		MinBackoff: httpMinBackoff,
+		MaxBackoff: httpMaxBackoff,
+	}
+}
 
-	if err != nil {
-		return err
+// NewHTTPTransport returns transport with connect and read timeouts
+func NewHTTPTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   httpConnectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   httpConnectTimeout,
+		ResponseHeaderTimeout: httpReadTimeout,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConnsPerHost:   4,
 	}
+}
 
-	err = readResponse(response, &collection)
+// HTTPClient is shared HTTP client making GET requests with retries
+type HTTPClient struct {
+	transport *http.Transport
+	client    *http.Client
+	// UserAgent header of requests
+	UserAgent string
+	// MaxRetries of request failing with network error or 5xx status
+	MaxRetries int
+	// MinBackoff is the delay before first retry
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+}
 
-	return err
+// Get makes GET request and decodes response to result. Requests failing
+// with network error or 5xx status are retried with exponential backoff
+// until MaxRetries is reached or context is canceled.
+func (c *HTTPClient) Get(ctx context.Context, rawURL string, result interface{}) error {
+	backoff := c.MinBackoff
+	for attempt := 0; ; attempt++ {
+		response, err := c.do(ctx, rawURL)
+		retry := err != nil || response.StatusCode >= 500
+		if !retry || attempt >= c.MaxRetries || ctx.Err() != nil {
+			if err != nil {
+				return err
+			}
+			return readResponse(response, result)
+		}
+		if err == nil {
+			// Drain body so that connection can be reused
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitter(backoff)):
+		}
+		backoff *= 2
+		if backoff > c.MaxBackoff {
+			backoff = c.MaxBackoff
+		}
+	}
 }
 
-// HTTPGet make Get request
-func HTTPGet(host string, path string, qp map[string]string, collection interface{}) error {
+// SetTransport replaces transport of requests e.g. with recording transport
+func (c *HTTPClient) SetTransport(rt http.RoundTripper) {
+	c.client.Transport = rt
+}
 
-	baseURL, _ := url.Parse(host)
-	endpoint, _ := baseURL.Parse(baseURL.RequestURI() + "/" + path)
-	query := endpoint.Query()
+// CloseIdleConnections closes keep-alive connections which are not in use
+func (c *HTTPClient) CloseIdleConnections() {
+	c.transport.CloseIdleConnections()
+}
 
-	for key, value := range qp {
-		query.Set(key, value)
+func (c *HTTPClient) do(ctx context.Context, rawURL string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
 	}
-
-	endpoint.RawQuery = query.Encode()
-
-	client := &http.Client{
-		Transport: http.DefaultTransport,
+	request = request.WithContext(ctx)
+	request.Header.Set("User-Agent", c.UserAgent)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Accept-Encoding", "gzip")
+	response, err := c.client.Do(request)
+	if err != nil {
+		// url.Error would include query with key and access token
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return nil, &TransportError{URL: request.URL, Err: err}
 	}
+	return response, nil
+}
 
-	response, err := client.Get(endpoint.String())
+// readResponse checks status of response first and decodes body to result.
+// Error bodies which are not API errors are truncated into HTTPStatusError.
+func readResponse(response *http.Response, result interface{}) error {
+	// close the body when done reading
+	defer response.Body.Close()
 
+	body, err := readBody(response)
 	if err != nil {
-		return err
+		return &TransportError{URL: response.Request.URL, Err: err}
 	}
 
-	err = readResponse(response, &collection)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		// API errors are JSON wrappers with error_id, decode these into result
+		// too so that backoff and quota of response are not lost
+		var wrapper struct {
+			ErrorID      int    `json:"error_id"`
+			ErrorName    string `json:"error_name"`
+			ErrorMessage string `json:"error_message"`
+		}
+		if json.Unmarshal(body, &wrapper) == nil && wrapper.ErrorID != 0 {
+			json.Unmarshal(body, result)
+			return &APIError{
+				ID:         wrapper.ErrorID,
+				Name:       wrapper.ErrorName,
+				Message:    wrapper.ErrorMessage,
+				StatusCode: response.StatusCode,
+			}
+		}
+		return &HTTPStatusError{
+			StatusCode: response.StatusCode,
+			Status:     response.Status,
+			Body:       truncateBody(body),
+		}
+	}
 
-	return err
+	if err = json.Unmarshal(body, result); err != nil {
+		return &DecodeError{Err: err, Body: truncateBody(body)}
+	}
+	return nil
 }
 
-// ReadResponse the response from http.Response
-func readResponse(response *http.Response, result interface{}) error {
-	// close the body when done reading
-	defer response.Body.Close()
+// readBody reads response body, gzip compressed body is decompressed also
+// when server compressed it without being asked
+func readBody(response *http.Response) ([]byte, error) {
+	reader := bufio.NewReader(response.Body)
+	magic, _ := reader.Peek(2)
+	if response.Header.Get("Content-Encoding") != "gzip" && !bytes.Equal(magic, gzipMagic) {
+		return ioutil.ReadAll(reader)
+	}
+	gz, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return ioutil.ReadAll(gz)
+}
 
-	bytes, err := ioutil.ReadAll(response.Body)
+var gzipMagic = []byte{0x1f, 0x8b}
 
-	if err != nil {
-		return err
+// truncateBody returns body shortened for error message
+func truncateBody(body []byte) string {
+	s := strings.TrimSpace(string(body))
+	if len(s) > httpMaxErrorBody {
+		s = s[:httpMaxErrorBody] + "…"
 	}
+	return s
+}
 
-	err = json.Unmarshal(bytes, result)
+// TransportError is network error of request, response was not received
+// or could not be read
+type TransportError struct {
+	URL *url.URL
+	Err error
+}
 
-	if err != nil {
-		return err
+// Error message, query of URL is omitted as it contains key and access token
+func (e *TransportError) Error() string {
+	if e.URL == nil {
+		return fmt.Sprintf("request failed: %s", e.Err.Error())
 	}
+	return fmt.Sprintf("request to %s://%s%s failed: %s", e.URL.Scheme, e.URL.Host, e.URL.Path, e.Err.Error())
+}
+
+// HTTPStatusError is unsuccessful response which is not API error e.g.
+// HTML error page of proxy
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
 
-	if response.StatusCode == 400 {
-		return fmt.Errorf("Bad Request: %s", string(bytes))
+// Error message
+func (e *HTTPStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("unexpected response %s", e.Status)
 	}
+	return fmt.Sprintf("unexpected response %s: %s", e.Status, e.Body)
+}
 
-	return nil
+// DecodeError is successful response which body could not be decoded
+type DecodeError struct {
+	Err  error
+	Body string
+}
+
+// Error message
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("invalid response: %s: %s", e.Err.Error(), e.Body)
 }
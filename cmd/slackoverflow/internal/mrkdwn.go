@@ -0,0 +1,285 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	mdFence    = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+	mdHeader   = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)\s*#*\s*$`)
+	mdListItem = regexp.MustCompile(`^(\s*)[*+-]\s+`)
+	mdLink     = regexp.MustCompile(`!?\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	mdAutoLink = regexp.MustCompile(`<((?:https?|ftp)://[^>\s]+)>`)
+	mdBold     = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	mdItalic   = regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*?\S)?)\*`)
+	htmlTag    = regexp.MustCompile(`<[^>]*>`)
+	whitespace = regexp.MustCompile(`\s+`)
+	// mrkdwnURLEscaper percent-encodes characters which end URL of mrkdwn link
+	mrkdwnURLEscaper = strings.NewReplacer("|", "%7C", "<", "%3C", ">", "%3E")
+)
+
+// mdBlock is either paragraph text or code block
+type mdBlock struct {
+	code  bool
+	lines []string
+}
+
+// MarkdownToMrkdwn converts Stack Exchange body_markdown into Slack mrkdwn.
+// Output is limited to about maxLen characters (no limit if maxLen <= 0) and
+// truncated only between lines so that code blocks and links stay intact.
+func MarkdownToMrkdwn(markdown string, maxLen int) string {
+	// Stack Exchange returns body_markdown with HTML entities encoded
+	src := html.UnescapeString(markdown)
+	src = strings.Replace(src, "\r\n", "\n", -1)
+
+	var out []string
+	length := 0
+	truncated := false
+	fits := func(line string) bool {
+		return maxLen <= 0 || length+utf8.RuneCountInString(line)+1 <= maxLen
+	}
+
+	for _, block := range parseMarkdownBlocks(src) {
+		if block.code {
+			code := []string{"```"}
+			for _, line := range block.lines {
+				line = escapeMrkdwn(strings.Replace(line, "```", "`​``", -1))
+				// Reserve space for closing fence
+				if !fits(line + "```") {
+					truncated = true
+					break
+				}
+				code = append(code, line)
+				length += utf8.RuneCountInString(line) + 1
+			}
+			if len(code) > 1 {
+				out = append(out, append(code, "```")...)
+				length += 8
+			}
+		} else {
+			for _, line := range block.lines {
+				rendered := convertMarkdownLine(line)
+				if !fits(rendered) {
+					// Cut long first line at word boundary, later lines are dropped
+					if remaining := maxLen - length; remaining > 40 && len(out) == 0 {
+						out = append(out, convertMarkdownLine(cutAtWord(line, remaining)))
+					}
+					truncated = true
+					break
+				}
+				out = append(out, rendered)
+				length += utf8.RuneCountInString(rendered) + 1
+			}
+		}
+		if truncated {
+			break
+		}
+	}
+
+	result := strings.TrimSpace(strings.Join(out, "\n"))
+	if truncated {
+		if strings.HasSuffix(result, "```") {
+			result += "\n…"
+		} else {
+			result += " …"
+		}
+	}
+	return result
+}
+
+// parseMarkdownBlocks splits markdown into text and code blocks, consecutive
+// blank lines are collapsed
+func parseMarkdownBlocks(src string) []mdBlock {
+	var blocks []mdBlock
+	var current *mdBlock
+	lines := strings.Split(src, "\n")
+	prevBlank := true
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		// Fenced code block
+		if m := mdFence.FindStringSubmatch(line); m != nil {
+			code := mdBlock{code: true}
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), m[1]) {
+					break
+				}
+				code.lines = append(code.lines, lines[i])
+			}
+			blocks = append(blocks, code)
+			current = nil
+			prevBlank = false
+			continue
+		}
+
+		// Indented code block must follow blank line
+		if prevBlank && isIndentedCode(line) {
+			code := mdBlock{code: true}
+			for ; i < len(lines); i++ {
+				if isIndentedCode(lines[i]) {
+					code.lines = append(code.lines, strings.TrimPrefix(strings.TrimPrefix(lines[i], "    "), "\t"))
+					continue
+				}
+				if strings.TrimSpace(lines[i]) == "" && i+1 < len(lines) && isIndentedCode(lines[i+1]) {
+					code.lines = append(code.lines, "")
+					continue
+				}
+				break
+			}
+			i--
+			blocks = append(blocks, code)
+			current = nil
+			prevBlank = false
+			continue
+		}
+
+		blank := strings.TrimSpace(line) == ""
+		if blank && (prevBlank || current == nil) {
+			prevBlank = true
+			continue
+		}
+		if current == nil {
+			blocks = append(blocks, mdBlock{})
+			current = &blocks[len(blocks)-1]
+		}
+		current.lines = append(current.lines, strings.TrimRight(line, " \t"))
+		prevBlank = blank
+	}
+	return blocks
+}
+
+func isIndentedCode(line string) bool {
+	return (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")) && strings.TrimSpace(line) != ""
+}
+
+// convertMarkdownLine converts single line of markdown text
+func convertMarkdownLine(line string) string {
+	if m := mdHeader.FindStringSubmatch(line); m != nil {
+		return "*" + convertMarkdownInline(m[1]) + "*"
+	}
+	prefix := ""
+	trimmed := strings.TrimLeft(line, " ")
+	if strings.HasPrefix(trimmed, ">") {
+		// Slack renders quote only with unescaped >
+		prefix = ">"
+		line = strings.TrimPrefix(trimmed, ">")
+	}
+	if loc := mdListItem.FindStringSubmatchIndex(line); loc != nil {
+		prefix += line[loc[2]:loc[3]] + "• "
+		line = line[loc[1]:]
+	}
+	return prefix + convertMarkdownInline(line)
+}
+
+// convertMarkdownInline converts inline code, links and emphasis
+func convertMarkdownInline(text string) string {
+	var out []string
+	for text != "" {
+		start := strings.Index(text, "`")
+		if start < 0 {
+			out = append(out, convertMarkdownText(text))
+			break
+		}
+		end := strings.Index(text[start+1:], "`")
+		if end < 0 {
+			out = append(out, convertMarkdownText(text))
+			break
+		}
+		end += start + 1
+		out = append(out, convertMarkdownText(text[:start]))
+		if code := text[start+1 : end]; code != "" {
+			out = append(out, "`"+escapeMrkdwn(code)+"`")
+		}
+		text = text[end+1:]
+	}
+	return strings.Join(out, "")
+}
+
+// convertMarkdownText converts text outside of inline code
+func convertMarkdownText(text string) string {
+	var out []string
+	for text != "" {
+		link := mdLink.FindStringSubmatchIndex(text)
+		auto := mdAutoLink.FindStringSubmatchIndex(text)
+		if link == nil && auto == nil {
+			out = append(out, convertEmphasis(escapeMrkdwn(text)))
+			break
+		}
+		if link == nil || (auto != nil && auto[0] < link[0]) {
+			out = append(out, convertEmphasis(escapeMrkdwn(text[:auto[0]])))
+			out = append(out, MrkdwnLink(text[auto[2]:auto[3]], ""))
+			text = text[auto[1]:]
+			continue
+		}
+		out = append(out, convertEmphasis(escapeMrkdwn(text[:link[0]])))
+		out = append(out, MrkdwnLink(text[link[4]:link[5]], text[link[2]:link[3]]))
+		text = text[link[1]:]
+	}
+	return strings.Join(out, "")
+}
+
+// convertEmphasis converts **bold** and *italic* to Slack *bold* and _italic_
+func convertEmphasis(text string) string {
+	// Mark bold first so that italic pass does not pick it up
+	text = mdBold.ReplaceAllString(text, "\x00$2\x00")
+	text = mdItalic.ReplaceAllString(text, "${1}_${2}_")
+	return strings.Replace(text, "\x00", "*", -1)
+}
+
+// HTMLToMrkdwn converts short HTML snippet such as inbox item body into plain
+// single line Slack mrkdwn of at most about maxLen characters.
+func HTMLToMrkdwn(body string, maxLen int) string {
+	text := html.UnescapeString(htmlTag.ReplaceAllString(body, " "))
+	text = strings.TrimSpace(whitespace.ReplaceAllString(text, " "))
+	if maxLen > 0 && utf8.RuneCountInString(text) > maxLen {
+		text = cutAtWord(text, maxLen) + "…"
+	}
+	return escapeMrkdwn(text)
+}
+
+// MrkdwnLink returns Slack mrkdwn link to href labeled with plain text label.
+// Only http and https links are linked, other ones are returned as text so
+// that untrusted input can not link to unexpected targets.
+func MrkdwnLink(href string, label string) string {
+	label = escapeMrkdwn(strings.Replace(label, "|", "/", -1))
+	lower := strings.ToLower(href)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		if label == "" {
+			return escapeMrkdwn(href)
+		}
+		return label
+	}
+	// Slack would read | as start of label and < > as end of link
+	href = escapeMrkdwn(mrkdwnURLEscaper.Replace(href))
+	if label == "" {
+		return "<" + href + ">"
+	}
+	return "<" + href + "|" + label + ">"
+}
+
+// escapeMrkdwn escapes control characters of Slack mrkdwn
+func escapeMrkdwn(text string) string {
+	text = strings.Replace(text, "&", "&amp;", -1)
+	text = strings.Replace(text, "<", "&lt;", -1)
+	return strings.Replace(text, ">", "&gt;", -1)
+}
+
+// cutAtWord returns at most n runes of text cut at last space
+func cutAtWord(text string, n int) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	runes := []rune(text)
+	cut := string(runes[:n])
+	if i := strings.LastIndex(cut, " "); i > n/2 {
+		cut = cut[:i]
+	}
+	return cut
+}
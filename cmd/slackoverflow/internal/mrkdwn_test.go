@@ -0,0 +1,89 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import "testing"
+
+func TestMarkdownToMrkdwn(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		maxLen   int
+		want     string
+	}{
+		{"code fence", "Try:\n\n```js\nif (a < b && c) {}\n```\ndone", 0,
+			"Try:\n\n```\nif (a &lt; b &amp;&amp; c) {}\n```\ndone"},
+		{"fence in code", "    a ``` b", 0,
+			"```\na `​`` b\n```"},
+		{"inline code", "use `<a-scene>` and **bold** *it*", 0,
+			"use `&lt;a-scene&gt;` and *bold* _it_"},
+		{"link", "see [docs](https://aframe.io/docs)", 0,
+			"see <https://aframe.io/docs|docs>"},
+		{"link with pipe in href", "[docs](http://evil.example|http://good.example)", 0,
+			"<http://evil.example%7Chttp://good.example|docs>"},
+		{"link with pipe in label", "[a|b](https://aframe.io)", 0,
+			"<https://aframe.io|a/b>"},
+		{"link with angle brackets", "[<b>](https://aframe.io/?q=<x)", 0,
+			"<https://aframe.io/?q=%3Cx|&lt;b&gt;>"},
+		{"link with unsafe scheme", "[click](javascript:alert(1)) [x](ftp://host)", 0,
+			"click) x"},
+		{"auto link", "<https://aframe.io|x> <ftp://host/file>", 0,
+			"<https://aframe.io%7Cx> ftp://host/file"},
+		{"html entities", "a &lt;b&gt; &amp; &quot;c&quot; &#39;d&#39;", 0,
+			"a &lt;b&gt; &amp; \"c\" 'd'"},
+		{"truncate in word", "first words of a rather long line that does not fit into the limit at all", 50,
+			"first words of a rather long line that does not …"},
+		{"truncate between lines", "line one is here\nline two is here\nline three", 34,
+			"line one is here\nline two is here …"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkdownToMrkdwn(tt.markdown, tt.maxLen); got != tt.want {
+				t.Errorf("MarkdownToMrkdwn(%q, %d)\ngot:  %q\nwant: %q", tt.markdown, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLToMrkdwn(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		maxLen int
+		want   string
+	}{
+		{"tags and entities", "<p>Use <code>&lt;a-entity&gt;</code> &amp;\n more</p>", 0,
+			"Use &lt;a-entity&gt; &amp; more"},
+		{"truncate in word", "<p>comment about positioning entities</p>", 20,
+			"comment about…"},
+		{"no link injection", "<p>&lt;https://evil.example|click&gt;</p>", 0,
+			"&lt;https://evil.example|click&gt;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTMLToMrkdwn(tt.body, tt.maxLen); got != tt.want {
+				t.Errorf("HTMLToMrkdwn(%q, %d)\ngot:  %q\nwant: %q", tt.body, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMrkdwnLink(t *testing.T) {
+	tests := []struct {
+		href  string
+		label string
+		want  string
+	}{
+		{"https://stackoverflow.com/q/1", "a | b <c> & d", "<https://stackoverflow.com/q/1|a / b &lt;c&gt; &amp; d>"},
+		{"https://stackoverflow.com/q/1?a=1&b=2", "", "<https://stackoverflow.com/q/1?a=1&amp;b=2>"},
+		{"javascript:alert(1)", "click", "click"},
+		{"mailto:a@b", "", "mailto:a@b"},
+	}
+	for _, tt := range tests {
+		if got := MrkdwnLink(tt.href, tt.label); got != tt.want {
+			t.Errorf("MrkdwnLink(%q, %q) = %q, want %q", tt.href, tt.label, got, tt.want)
+		}
+	}
+}
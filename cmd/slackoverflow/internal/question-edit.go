@@ -0,0 +1,79 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewStackExchangeQuestionEdit compares stored question with question received
+// from API, returns false if neither title nor tags have changed
+func NewStackExchangeQuestionEdit(existing StackExchangeQuestion, q QuestionObj) (StackExchangeQuestionEdit, bool) {
+	edit := StackExchangeQuestionEdit{
+		QID:      q.QID,
+		Detected: time.Now().UTC(),
+	}
+	if existing.Title != q.Title {
+		edit.OldTitle = existing.Title
+		edit.NewTitle = q.Title
+	}
+	oldTags := splitTags(existing.Tags)
+	edit.TagsAdded = tagsDiff(q.Tags, oldTags)
+	edit.TagsRemoved = tagsDiff(oldTags, q.Tags)
+
+	changed := edit.NewTitle != "" || len(edit.TagsAdded) > 0 || len(edit.TagsRemoved) > 0
+	return edit, changed
+}
+
+// QuestionEditor returns display name of user who made latest title or tags
+// revision of question, empty string if it could not be determined
+func (s *StackExchangeClient) QuestionEditor(QID int, site string) string {
+	revisions := s.PostsRevisions()
+	revisions.Parameters.Set("site", site)
+	if ok, _ := revisions.Get(strconv.Itoa(QID)); !ok {
+		return ""
+	}
+	var latest *RevisionObj
+	for i, rev := range revisions.Result.Items {
+		if rev.Title == "" && len(rev.Tags) == 0 {
+			continue
+		}
+		if latest == nil || rev.CreationDate > latest.CreationDate {
+			latest = &revisions.Result.Items[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.User.DisplayName
+}
+
+// splitTags stored as semicolon delimited list
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, ";")
+}
+
+// tagsDiff returns tags in a which are not in b
+func tagsDiff(a []string, b []string) []string {
+	var diff []string
+	for _, tag := range a {
+		found := false
+		for _, t := range b {
+			if tag == t {
+				found = true
+				break
+			}
+		}
+		if !found {
+			diff = append(diff, tag)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,289 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/howi-ce/howi/std/errors"
+	"github.com/nlopes/slack"
+)
+
+// SlackEventHandlerFunc handles Slack Events API event of given type
+type SlackEventHandlerFunc func(e SlackEvent) error
+
+// SlackCommandHandlerFunc handles Slack slash command
+type SlackCommandHandlerFunc func(cmd SlackCommand) (*SlackResponse, error)
+
+// SlackInteractionHandlerFunc handles Slack interactive message payload
+type SlackInteractionHandlerFunc func(cb slack.AttachmentActionCallback) (*SlackResponse, error)
+
+// SlackEvent is Slack Events API callback
+type SlackEvent struct {
+	Token     string          `json:"token"`
+	TeamID    string          `json:"team_id"`
+	APIAppID  string          `json:"api_app_id"`
+	Type      string          `json:"type"`
+	Challenge string          `json:"challenge"`
+	EventID   string          `json:"event_id"`
+	EventTime int64           `json:"event_time"`
+	Event     json.RawMessage `json:"event"`
+}
+
+// InnerType returns type of the wrapped event
+func (e *SlackEvent) InnerType() string {
+	inner := struct {
+		Type string `json:"type"`
+	}{}
+	if len(e.Event) > 0 {
+		_ = json.Unmarshal(e.Event, &inner)
+	}
+	return inner.Type
+}
+
+// SlackCommand is Slack slash command invocation
+type SlackCommand struct {
+	Token       string `json:"token"`
+	TeamID      string `json:"team_id"`
+	TeamDomain  string `json:"team_domain"`
+	ChannelID   string `json:"channel_id"`
+	ChannelName string `json:"channel_name"`
+	UserID      string `json:"user_id"`
+	UserName    string `json:"user_name"`
+	Command     string `json:"command"`
+	Text        string `json:"text"`
+	ResponseURL string `json:"response_url"`
+	TriggerID   string `json:"trigger_id"`
+}
+
+// SlackResponse returned to Slack for slash commands and interactive payloads
+type SlackResponse struct {
+	ResponseType    string             `json:"response_type,omitempty"`
+	ReplaceOriginal bool               `json:"replace_original,omitempty"`
+	Text            string             `json:"text,omitempty"`
+	Attachments     []slack.Attachment `json:"attachments,omitempty"`
+}
+
+// NewSlackDispatcher returns dispatcher without any handlers registered
+func NewSlackDispatcher() *SlackDispatcher {
+	return &SlackDispatcher{
+		events:       make(map[string]SlackEventHandlerFunc),
+		commands:     make(map[string]SlackCommandHandlerFunc),
+		interactions: make(map[string]SlackInteractionHandlerFunc),
+	}
+}
+
+// SlackDispatcher routes Slack events, slash commands and interactive payloads
+// to registered handlers, regardless of whether they were received over HTTP
+// or Socket Mode.
+type SlackDispatcher struct {
+	mu            sync.RWMutex
+	signingSecret string
+	events        map[string]SlackEventHandlerFunc
+	commands      map[string]SlackCommandHandlerFunc
+	interactions  map[string]SlackInteractionHandlerFunc
+}
+
+// SetSigningSecret used to verify requests received over HTTP
+func (d *SlackDispatcher) SetSigningSecret(secret string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.signingSecret = secret
+}
+
+// HandleEvent registers handler for Events API event type e.g. reaction_added
+func (d *SlackDispatcher) HandleEvent(eventType string, fn SlackEventHandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.events[eventType] = fn
+}
+
+// HandleCommand registers handler for slash command e.g. /slackoverflow
+func (d *SlackDispatcher) HandleCommand(command string, fn SlackCommandHandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.commands[command] = fn
+}
+
+// HandleInteraction registers handler for interactive messages with given callback id
+func (d *SlackDispatcher) HandleInteraction(callbackID string, fn SlackInteractionHandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.interactions[callbackID] = fn
+}
+
+// DispatchEvent to registered handler, events without handler are ignored
+func (d *SlackDispatcher) DispatchEvent(e SlackEvent) error {
+	d.mu.RLock()
+	fn, ok := d.events[e.InnerType()]
+	d.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return fn(e)
+}
+
+// DispatchCommand to registered handler
+func (d *SlackDispatcher) DispatchCommand(cmd SlackCommand) (*SlackResponse, error) {
+	d.mu.RLock()
+	fn, ok := d.commands[cmd.Command]
+	d.mu.RUnlock()
+	if !ok {
+		return nil, errors.Newf("no handler for slash command %q", cmd.Command)
+	}
+	return fn(cmd)
+}
+
+// DispatchInteraction to registered handler
+func (d *SlackDispatcher) DispatchInteraction(cb slack.AttachmentActionCallback) (*SlackResponse, error) {
+	d.mu.RLock()
+	fn, ok := d.interactions[cb.CallbackID]
+	d.mu.RUnlock()
+	if !ok {
+		return nil, errors.Newf("no handler for interaction %q", cb.CallbackID)
+	}
+	return fn(cb)
+}
+
+// ServeMux returns HTTP endpoints for events, slash commands and interactive
+// payloads which are served from /slack/events, /slack/commands and /slack/interactive.
+// Every request must be signed with signing secret.
+func (d *SlackDispatcher) ServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slack/events", d.verified(d.serveEvents))
+	mux.HandleFunc("/slack/commands", d.verified(d.serveCommands))
+	mux.HandleFunc("/slack/interactive", d.verified(d.serveInteractive))
+	return mux
+}
+
+// verified rejects requests without valid Slack signature
+// https://api.slack.com/authentication/verifying-requests-from-slack
+func (d *SlackDispatcher) verified(next http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		d.mu.RLock()
+		secret := d.signingSecret
+		d.mu.RUnlock()
+		if err := VerifySlackSignature(secret, r.Header, body, time.Now()); err != nil {
+			http.Error(rw, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		next(rw, r)
+	}
+}
+
+// VerifySlackSignature checks X-Slack-Signature of request body, requests
+// older than 5 minutes are rejected to prevent replay attacks.
+func VerifySlackSignature(secret string, h http.Header, body []byte, now time.Time) error {
+	if secret == "" {
+		return errors.New("Slack signing secret is not configured")
+	}
+	ts := h.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.New("invalid X-Slack-Request-Timestamp")
+	}
+	if diff := now.Unix() - sec; diff > 5*60 || diff < -5*60 {
+		return errors.New("Slack request timestamp is too old")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(h.Get("X-Slack-Signature"))) {
+		return errors.New("invalid Slack signature")
+	}
+	return nil
+}
+
+func (d *SlackDispatcher) serveEvents(rw http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	e := SlackEvent{}
+	if err := json.Unmarshal(body, &e); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if e.Type == "url_verification" {
+		rw.Header().Set("Content-Type", "text/plain")
+		rw.Write([]byte(e.Challenge))
+		return
+	}
+	if err := d.DispatchEvent(e); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+func (d *SlackDispatcher) serveCommands(rw http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := d.DispatchCommand(slackCommandFromForm(r.PostForm))
+	writeSlackResponse(rw, resp, err)
+}
+
+func (d *SlackDispatcher) serveInteractive(rw http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	cb := slack.AttachmentActionCallback{}
+	if err := json.Unmarshal([]byte(r.PostForm.Get("payload")), &cb); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := d.DispatchInteraction(cb)
+	writeSlackResponse(rw, resp, err)
+}
+
+// slackCommandFromForm maps form encoded slash command
+func slackCommandFromForm(f url.Values) SlackCommand {
+	return SlackCommand{
+		Token:       f.Get("token"),
+		TeamID:      f.Get("team_id"),
+		TeamDomain:  f.Get("team_domain"),
+		ChannelID:   f.Get("channel_id"),
+		ChannelName: f.Get("channel_name"),
+		UserID:      f.Get("user_id"),
+		UserName:    f.Get("user_name"),
+		Command:     f.Get("command"),
+		Text:        f.Get("text"),
+		ResponseURL: f.Get("response_url"),
+		TriggerID:   f.Get("trigger_id"),
+	}
+}
+
+func writeSlackResponse(rw http.ResponseWriter, resp *SlackResponse, err error) {
+	if err != nil {
+		resp = &SlackResponse{ResponseType: "ephemeral", Text: err.Error()}
+	}
+	if resp == nil {
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(resp)
+}
@@ -0,0 +1,159 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/howi-ce/howi/std/errors"
+	"golang.org/x/net/websocket"
+)
+
+// newSlackSocketModeStandIn starts local stand-in for Slack Socket Mode which
+// serves apps.connections.open and WebSocket endpoint. Point SlackSocketMode
+// apiHost to URL() to exercise the client without network access.
+func newSlackSocketModeStandIn() *slackSocketModeStandIn {
+	s := &slackSocketModeStandIn{
+		acks:      make(chan socketModeAck, 100),
+		connected: make(chan struct{}, 10),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apps.connections.open", s.serveConnectionsOpen)
+	mux.Handle("/link", websocket.Handler(s.serveLink))
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+// slackSocketModeStandIn is local Socket Mode server
+type slackSocketModeStandIn struct {
+	mu          sync.Mutex
+	server      *httptest.Server
+	conn        *websocket.Conn
+	connections int
+	envelopes   int
+	acks        chan socketModeAck
+	connected   chan struct{}
+}
+
+// URL of the stand-in to be used as Slack API host
+func (s *slackSocketModeStandIn) URL() string {
+	return s.server.URL
+}
+
+// Connections returns number of WebSocket connections accepted so far
+func (s *slackSocketModeStandIn) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// WaitConnected blocks until client has connected or timeout is reached
+func (s *slackSocketModeStandIn) WaitConnected(timeout time.Duration) error {
+	select {
+	case <-s.connected:
+		return nil
+	case <-time.After(timeout):
+		return errors.New("socket mode client did not connect")
+	}
+}
+
+// Send envelope of given type (events_api, slash_commands, interactive)
+// and wait for client to acknowledge it. Returns response payload if any.
+func (s *slackSocketModeStandIn) Send(envelopeType string, payload interface{}, timeout time.Duration) (*SlackResponse, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.envelopes++
+	env := socketModeEnvelope{
+		EnvelopeID:             fmt.Sprintf("envelope-%d", s.envelopes),
+		Type:                   envelopeType,
+		AcceptsResponsePayload: envelopeType != "events_api",
+		Payload:                raw,
+	}
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return nil, errors.New("no socket mode client connected")
+	}
+	if err := websocket.JSON.Send(conn, env); err != nil {
+		return nil, err
+	}
+	select {
+	case ack := <-s.acks:
+		if ack.EnvelopeID != env.EnvelopeID {
+			return nil, errors.Newf("expected ack for %s got %s", env.EnvelopeID, ack.EnvelopeID)
+		}
+		return ack.Payload, nil
+	case <-time.After(timeout):
+		return nil, errors.Newf("envelope %s was not acknowledged", env.EnvelopeID)
+	}
+}
+
+// Disconnect asks connected client to reconnect, same as Slack does
+// when connection is refreshed.
+func (s *slackSocketModeStandIn) Disconnect() error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	return websocket.JSON.Send(conn, socketModeEnvelope{Type: "disconnect", Reason: "refresh_requested"})
+}
+
+// Drop closes current connection without notice
+func (s *slackSocketModeStandIn) Drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// Close the stand-in server
+func (s *slackSocketModeStandIn) Close() {
+	s.Drop()
+	s.server.Close()
+}
+
+func (s *slackSocketModeStandIn) serveConnectionsOpen(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		fmt.Fprint(rw, `{"ok":false,"error":"not_authed"}`)
+		return
+	}
+	wsURL := "ws" + strings.TrimPrefix(s.server.URL, "http") + "/link"
+	json.NewEncoder(rw).Encode(map[string]interface{}{"ok": true, "url": wsURL})
+}
+
+func (s *slackSocketModeStandIn) serveLink(conn *websocket.Conn) {
+	s.mu.Lock()
+	s.conn = conn
+	s.connections++
+	s.mu.Unlock()
+	if err := websocket.JSON.Send(conn, socketModeEnvelope{Type: "hello"}); err != nil {
+		return
+	}
+	select {
+	case s.connected <- struct{}{}:
+	default:
+	}
+	for {
+		ack := socketModeAck{}
+		if err := websocket.JSON.Receive(conn, &ack); err != nil {
+			return
+		}
+		s.acks <- ack
+	}
+}
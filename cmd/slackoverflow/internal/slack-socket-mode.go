@@ -0,0 +1,247 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/std/errors"
+	"github.com/nlopes/slack"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	socketModeMinBackoff = 1 * time.Second
+	socketModeMaxBackoff = 2 * time.Minute
+	// socketModeAckTimeout leaves margin to 3 seconds Slack waits for ack
+	socketModeAckTimeout = 2 * time.Second
+)
+
+// NewSlackSocketMode returns Socket Mode client which receives Slack payloads
+// over WebSocket opened with app-level token (xapp-...)
+func NewSlackSocketMode(apiHost string, appToken string, d *SlackDispatcher) *SlackSocketMode {
+	if apiHost == "" {
+		apiHost = SlackAPIHost
+	}
+	return &SlackSocketMode{
+		apiHost:    strings.TrimRight(apiHost, "/"),
+		appToken:   appToken,
+		dispatcher: d,
+		MinBackoff: socketModeMinBackoff,
+		MaxBackoff: socketModeMaxBackoff,
+		AckTimeout: socketModeAckTimeout,
+	}
+}
+
+// SlackSocketMode client
+type SlackSocketMode struct {
+	apiHost    string
+	appToken   string
+	dispatcher *SlackDispatcher
+	// MinBackoff is the delay before first reconnect attempt
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between reconnect attempts
+	MaxBackoff time.Duration
+	// AckTimeout is how long handler may take to respond with payload,
+	// envelope is acknowledged without payload after it
+	AckTimeout time.Duration
+}
+
+// socketModeJob is received envelope waiting for dispatch, response is
+// delivered to result if envelope accepts response payload
+type socketModeJob struct {
+	env    socketModeEnvelope
+	result chan *SlackResponse
+}
+
+// socketModeEnvelope is message received over Socket Mode connection
+type socketModeEnvelope struct {
+	EnvelopeID             string          `json:"envelope_id"`
+	Type                   string          `json:"type"`
+	Reason                 string          `json:"reason"`
+	AcceptsResponsePayload bool            `json:"accepts_response_payload"`
+	Payload                json.RawMessage `json:"payload"`
+}
+
+// socketModeAck acknowledges received envelope
+type socketModeAck struct {
+	EnvelopeID string         `json:"envelope_id"`
+	Payload    *SlackResponse `json:"payload,omitempty"`
+}
+
+// Run Socket Mode client until context is canceled, reconnecting with
+// exponential backoff when connection is lost.
+func (s *SlackSocketMode) Run(ctx context.Context, w *cli.Worker) error {
+	backoff := s.MinBackoff
+	for {
+		connected, err := s.connect(ctx, w)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			backoff = s.MinBackoff
+		}
+		if err != nil {
+			w.Log.Warningf("Slack Socket Mode: %s, reconnecting in %s", err.Error(), backoff)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(jitter(backoff)):
+		}
+		if !connected {
+			backoff *= 2
+			if backoff > s.MaxBackoff {
+				backoff = s.MaxBackoff
+			}
+		}
+	}
+}
+
+// connect opens new connection and serves it until it is closed. Returns true
+// if hello was received from Slack.
+func (s *SlackSocketMode) connect(ctx context.Context, w *cli.Worker) (bool, error) {
+	wsURL, err := s.connectionURL()
+	if err != nil {
+		return false, err
+	}
+	conn, err := websocket.Dial(wsURL, "", s.apiHost)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	// Envelopes are dispatched in order by single worker so that slow
+	// handlers never delay acknowledging following envelopes
+	jobs := make(chan socketModeJob, 64)
+	defer close(jobs)
+	go s.work(w, jobs)
+
+	connected := false
+	for {
+		env := socketModeEnvelope{}
+		if err := websocket.JSON.Receive(conn, &env); err != nil {
+			return connected, err
+		}
+		switch env.Type {
+		case "hello":
+			connected = true
+			w.Log.Ok("Slack Socket Mode: connected")
+		case "disconnect":
+			w.Log.Infof("Slack Socket Mode: disconnect requested (%s)", env.Reason)
+			return connected, nil
+		default:
+			ack := socketModeAck{EnvelopeID: env.EnvelopeID}
+			job := socketModeJob{env: env}
+			if env.AcceptsResponsePayload {
+				job.result = make(chan *SlackResponse, 1)
+			}
+			jobs <- job
+			if job.result != nil {
+				select {
+				case ack.Payload = <-job.result:
+				case <-time.After(s.AckTimeout):
+					w.Log.Warningf("Slack Socket Mode (%s): handler did not respond in %s, acknowledged without response", env.Type, s.AckTimeout)
+				}
+			}
+			if env.EnvelopeID != "" {
+				if err := websocket.JSON.Send(conn, ack); err != nil {
+					return connected, err
+				}
+			}
+		}
+	}
+}
+
+// work dispatches envelopes until jobs is closed
+func (s *SlackSocketMode) work(w *cli.Worker, jobs <-chan socketModeJob) {
+	for job := range jobs {
+		resp, err := s.dispatch(job.env)
+		if err != nil {
+			w.Log.Errorf("Slack Socket Mode (%s): %s", job.env.Type, err.Error())
+		}
+		if job.result != nil {
+			job.result <- resp
+		}
+	}
+}
+
+// dispatch envelope payload to same handlers as HTTP endpoints
+func (s *SlackSocketMode) dispatch(env socketModeEnvelope) (*SlackResponse, error) {
+	switch env.Type {
+	case "events_api":
+		e := SlackEvent{}
+		if err := json.Unmarshal(env.Payload, &e); err != nil {
+			return nil, err
+		}
+		return nil, s.dispatcher.DispatchEvent(e)
+	case "slash_commands":
+		cmd := SlackCommand{}
+		if err := json.Unmarshal(env.Payload, &cmd); err != nil {
+			return nil, err
+		}
+		return s.dispatcher.DispatchCommand(cmd)
+	case "interactive":
+		cb := slack.AttachmentActionCallback{}
+		if err := json.Unmarshal(env.Payload, &cb); err != nil {
+			return nil, err
+		}
+		return s.dispatcher.DispatchInteraction(cb)
+	}
+	return nil, errors.Newf("unknown envelope type %q", env.Type)
+}
+
+// connectionURL requests WebSocket URL with apps.connections.open
+func (s *SlackSocketMode) connectionURL() (string, error) {
+	req, err := http.NewRequest("POST", s.apiHost+"/apps.connections.open", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.appToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	client := &http.Client{Timeout: 30 * time.Second}
+	response, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	result := struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		URL   string `json:"url"`
+	}{}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if !result.OK {
+		return "", errors.Newf("apps.connections.open: %s", result.Error)
+	}
+	return result.URL, nil
+}
+
+// jitter returns random duration between d/2 and d
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
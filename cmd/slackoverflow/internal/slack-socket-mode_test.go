@@ -0,0 +1,174 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+const standInTimeout = 5 * time.Second
+
+// startSocketMode runs client against new stand-in until test ends
+func startSocketMode(t *testing.T, d *SlackDispatcher) (*slackSocketModeStandIn, context.CancelFunc) {
+	standIn := newSlackSocketModeStandIn()
+	client := NewSlackSocketMode(standIn.URL(), "xapp-test", d)
+	client.MinBackoff = 10 * time.Millisecond
+	client.MaxBackoff = 50 * time.Millisecond
+	client.AckTimeout = 200 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		client.Run(ctx, newTestWorker())
+		close(done)
+	}()
+	if err := standIn.WaitConnected(standInTimeout); err != nil {
+		cancel()
+		standIn.Close()
+		t.Fatal(err)
+	}
+	return standIn, func() {
+		cancel()
+		standIn.Close()
+		<-done
+	}
+}
+
+func TestSlackSocketModeAcknowledgesEnvelopes(t *testing.T) {
+	d := NewSlackDispatcher()
+	d.HandleCommand("/slackoverflow", func(cmd SlackCommand) (*SlackResponse, error) {
+		return &SlackResponse{Text: "hello " + cmd.UserName}, nil
+	})
+	events := make(chan SlackEvent, 1)
+	d.HandleEvent("reaction_added", func(e SlackEvent) error {
+		events <- e
+		return nil
+	})
+	standIn, stop := startSocketMode(t, d)
+	defer stop()
+
+	resp, err := standIn.Send("slash_commands", SlackCommand{Command: "/slackoverflow", UserName: "ada"}, standInTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Text != "hello ada" {
+		t.Errorf("expected response payload %q, got %+v", "hello ada", resp)
+	}
+
+	event := map[string]interface{}{
+		"type":     "event_callback",
+		"event_id": "Ev1",
+		"event":    map[string]string{"type": "reaction_added"},
+	}
+	resp, err = standIn.Send("events_api", event, standInTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != nil {
+		t.Errorf("events must be acknowledged without payload, got %+v", resp)
+	}
+	select {
+	case e := <-events:
+		if e.EventID != "Ev1" {
+			t.Errorf("expected event Ev1, got %q", e.EventID)
+		}
+	case <-time.After(standInTimeout):
+		t.Error("event was not dispatched")
+	}
+
+	// Envelopes without handler are still acknowledged
+	if _, err := standIn.Send("slash_commands", SlackCommand{Command: "/unknown"}, standInTimeout); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSlackSocketModeAcknowledgesBeforeSlowHandlers(t *testing.T) {
+	release := make(chan struct{})
+	d := NewSlackDispatcher()
+	d.HandleEvent("reaction_added", func(e SlackEvent) error {
+		<-release
+		return nil
+	})
+	d.HandleCommand("/slow", func(cmd SlackCommand) (*SlackResponse, error) {
+		<-release
+		return &SlackResponse{Text: "too late"}, nil
+	})
+	d.HandleCommand("/ping", func(cmd SlackCommand) (*SlackResponse, error) {
+		return &SlackResponse{Text: "pong"}, nil
+	})
+	standIn, stop := startSocketMode(t, d)
+	defer stop()
+	defer close(release)
+
+	// Event handler blocks, but event is acknowledged right away
+	event := map[string]interface{}{
+		"type":  "event_callback",
+		"event": map[string]string{"type": "reaction_added"},
+	}
+	if _, err := standIn.Send("events_api", event, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	// Command waiting behind blocked handler is acknowledged without payload
+	start := time.Now()
+	resp, err := standIn.Send("slash_commands", SlackCommand{Command: "/slow"}, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != nil {
+		t.Errorf("slow command acknowledged with payload %+v", resp)
+	}
+	if elapsed := time.Since(start); elapsed > 900*time.Millisecond {
+		t.Errorf("slow command acknowledged after %s", elapsed)
+	}
+
+	release <- struct{}{}
+	release <- struct{}{}
+	resp, err = standIn.Send("slash_commands", SlackCommand{Command: "/ping"}, standInTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Text != "pong" {
+		t.Errorf("expected pong, got %+v", resp)
+	}
+}
+
+func TestSlackSocketModeReconnects(t *testing.T) {
+	d := NewSlackDispatcher()
+	d.HandleCommand("/ping", func(cmd SlackCommand) (*SlackResponse, error) {
+		return &SlackResponse{Text: "pong"}, nil
+	})
+	standIn, stop := startSocketMode(t, d)
+	defer stop()
+
+	// Slack asks client to reconnect when connection is refreshed
+	if err := standIn.Disconnect(); err != nil {
+		t.Fatal(err)
+	}
+	if err := standIn.WaitConnected(standInTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if n := standIn.Connections(); n != 2 {
+		t.Errorf("expected 2 connections after disconnect, got %d", n)
+	}
+
+	// Dropped connection is reopened with backoff
+	standIn.Drop()
+	if err := standIn.WaitConnected(standInTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if n := standIn.Connections(); n != 3 {
+		t.Errorf("expected 3 connections after drop, got %d", n)
+	}
+
+	// Envelopes are acknowledged over new connection
+	resp, err := standIn.Send("slash_commands", SlackCommand{Command: "/ping"}, standInTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Text != "pong" {
+		t.Errorf("expected pong, got %+v", resp)
+	}
+}
@@ -5,19 +5,33 @@
 package internal
 
 import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/howi-ce/howi/addon/application/plugin/cli"
 	"github.com/howi-ce/howi/lib/filesystem/path"
 	"github.com/howi-ce/howi/std/errors"
+	"github.com/nlopes/slack"
 )
 
 const (
 	// ErrNotConfigured is used when SlackOverflow is not configured
 	ErrNotConfigured = "You must execute 'slackoverflow reconfigure' or correct errors in ~/.slackoverflow/slackoverflow.yaml"
+	// ErrAccessTokenExpired is used when Stack Exchange access token is not valid anymore
+	ErrAccessTokenExpired = "Stack Exchange access token has expired or was revoked, run 'slackoverflow stackexchange login'"
+	// SlackAPIHost is default Slack Web API host
+	SlackAPIHost = "https://slack.com/api"
 )
 
 // NewSlackOverflow instance
 func NewSlackOverflow() *SlackOverflow {
-	return &SlackOverflow{}
+	return &SlackOverflow{
+		SlackDispatcher: NewSlackDispatcher(),
+	}
 }
 
 // SlackOverflow application instance
@@ -29,6 +43,13 @@ type SlackOverflow struct {
 	Config           Config
 	DB               Database
 	StackExchange    StackExchangeClient
+	SlackDispatcher  *SlackDispatcher
+	// DryRun is set with --dry-run
+	DryRun *DryRun
+	// transport of --record and --replay, nil otherwise
+	transport http.RoundTripper
+	// filterMu guards resolving of question filter
+	filterMu sync.Mutex
 }
 
 // Load SlackOverflow and try to load configuration from given path
@@ -78,6 +99,16 @@ func (so *SlackOverflow) IsConfigured() (bool, error) {
 	return false, errors.New(ErrNotConfigured)
 }
 
+// SaveConfig writes configuration file, on dry run saving is recorded as
+// action instead
+func (so *SlackOverflow) SaveConfig() error {
+	if so.DryRun.Enabled() {
+		so.DryRun.Add("save config", "", so.Config.file)
+		return nil
+	}
+	return so.Config.Save()
+}
+
 // Session loads everything for SlackOverfloe
 func (so *SlackOverflow) Session(w *cli.Worker) error {
 	if err := so.Load(w); err != nil {
@@ -86,17 +117,265 @@ func (so *SlackOverflow) Session(w *cli.Worker) error {
 	if ok, err := so.IsConfigured(); !ok && err != nil {
 		return err
 	}
+	if err := so.httpRecording(w); err != nil {
+		return err
+	}
+	if err := so.dryRun(w); err != nil {
+		return err
+	}
 	err := so.DB.VerifyTables(w)
 
 	so.StackExchange.SetHost(so.Config.StackExchange.APIHost)
 	so.StackExchange.SetAPIVersion(so.Config.StackExchange.APIVersion)
 	so.StackExchange.SetKey(so.Config.StackExchange.Key)
+	if token := so.Config.StackExchange.AccessToken; token != "" {
+		if so.Config.StackExchange.AccessTokenExpired(time.Now()) {
+			w.Log.Warning(ErrAccessTokenExpired)
+		} else {
+			so.StackExchange.SetAccessToken(token)
+		}
+	}
+	if err == nil {
+		err = so.validateStackExchangeConfig()
+	}
+
+	so.SlackDispatcher.SetSigningSecret(so.Config.Slack.SigningSecret)
+
+	return err
+}
+
+// httpRecording records HTTP requests to --record dir or replays them from
+// --replay dir. Transport is passed to every API client, see HTTPTransport.
+func (so *SlackOverflow) httpRecording(w *cli.Worker) error {
+	record, _ := w.Flag("record")
+	replay, _ := w.Flag("replay")
+	var transport http.RoundTripper
+	var err error
+	switch {
+	case record.Present() && replay.Present():
+		return errors.New("--record and --replay can not be used together")
+	case record.Present():
+		transport, err = NewRecordingTransport(record.Value().String(), nil)
+		w.Log.Noticef("Recording HTTP requests to %s", record.Value().String())
+	case replay.Present():
+		transport, err = NewReplayTransport(replay.Value().String())
+		w.Log.Noticef("Replaying HTTP requests from %s", replay.Value().String())
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	so.SetHTTPTransport(transport)
+	return nil
+}
+
+// SetHTTPTransport makes Stack Exchange, OAuth and Slack API clients use
+// transport instead of their default one
+func (so *SlackOverflow) SetHTTPTransport(transport http.RoundTripper) {
+	so.transport = transport
+	so.StackExchange.SetTransport(transport)
+}
+
+// HTTPTransport returns transport of --record or --replay, nil if neither
+// is used in which case clients use their default transport
+func (so *SlackOverflow) HTTPTransport() http.RoundTripper {
+	return so.transport
+}
+
+// SlackClient returns Slack API client of configured token and API host
+func (so *SlackOverflow) SlackClient() *SlackClient {
+	setSlackAPI(so.Config.Slack.APIHost)
+	setSlackTransport(so.transport)
+	return &SlackClient{Client: slack.New(so.Config.Slack.Token), dryRun: so.DryRun}
+}
+
+var (
+	// slackAPIMu guards API URL and HTTP client which are package level in
+	// Slack client
+	slackAPIMu sync.Mutex
+	// slackTransport is transport of HTTP client set to Slack client
+	slackTransport http.RoundTripper
+)
+
+func setSlackAPI(host string) {
+	apiURL := SlackAPIURL(host)
+	slackAPIMu.Lock()
+	defer slackAPIMu.Unlock()
+	if slack.SLACK_API != apiURL {
+		slack.SLACK_API = apiURL
+	}
+}
+
+// setSlackTransport makes Slack client use transport, nil restores default
+func setSlackTransport(transport http.RoundTripper) {
+	slackAPIMu.Lock()
+	defer slackAPIMu.Unlock()
+	if slackTransport != transport {
+		slackTransport = transport
+		slack.SetHTTPClient(&http.Client{Transport: transport})
+	}
+}
+
+// SlackAPIURL returns base URL of Slack Web API methods, default is used
+// if host is empty
+func SlackAPIURL(host string) string {
+	if host == "" {
+		host = SlackAPIHost
+	}
+	return strings.TrimRight(host, "/") + "/"
+}
+
+// QuestionFilter makes client request exactly the fields QuestionObj decodes,
+// it must be called before question queries are created. Filter is created
+// once per field set and stored in config. DefaultQuestionFilter is used on
+// failure and on dry run, which must not spend quota on creating it.
+func (so *SlackOverflow) QuestionFilter(w *cli.Worker) {
+	so.filterMu.Lock()
+	defer so.filterMu.Unlock()
+	if so.StackExchange.questionFilter != "" {
+		return
+	}
+	fields := QuestionFilterFields(so.Config.StackExchange.QuestionBody)
+	name := FilterName(QuestionFilterPrefix, fields)
+	filter, ok := so.Config.StackExchange.Filters[name]
+	if !ok && so.DryRun.Enabled() {
+		so.DryRun.Add("create filter", "", name)
+		filter = DefaultQuestionFilter
+	} else if !ok {
+		created, err := so.StackExchange.FiltersCreate("none", fields, nil)
+		if err != nil {
+			w.Log.Warningf("Stack Exchange: using default question filter, %s", err.Error())
+			return
+		}
+		filter = created.Filter
+		// Filters of previous field sets are not needed anymore
+		for n := range so.Config.StackExchange.Filters {
+			if strings.HasPrefix(n, QuestionFilterPrefix) {
+				delete(so.Config.StackExchange.Filters, n)
+			}
+		}
+		so.Config.StackExchange.SetFilter(name, filter)
+		if err = so.SaveConfig(); err != nil {
+			w.Log.Error(err)
+		}
+		w.Log.Okf("Stack Exchange: created question filter %s (%s)", name, filter)
+	}
+	so.StackExchange.SetQuestionFilter(filter)
+}
+
+// SearchAdvanced returns search query with site and parameters from config
+func (so *SlackOverflow) SearchAdvanced() (*SearchAdvanced, error) {
+	searchAdvanced := so.StackExchange.SearchAdvanced()
+	err := so.applyParameters(&searchAdvanced.Parameters, "search-advanced", so.Config.StackExchange.SearchAdvanced)
+	return searchAdvanced, err
+}
+
+// Questions returns questions query with site and parameters from config
+func (so *SlackOverflow) Questions() (*Questions, error) {
+	questions := so.StackExchange.Questions()
+	err := so.applyParameters(&questions.Parameters, "questions", so.Config.StackExchange.Questions)
+	return questions, err
+}
+
+// MeInboxUnread returns unread inbox query of the authorized user on configured site
+func (so *SlackOverflow) MeInboxUnread() (*MeInboxUnread, error) {
+	inbox := so.StackExchange.MeInboxUnread()
+	err := so.applyParameters(&inbox.Parameters, "inbox", nil)
+	return inbox, err
+}
+
+// MeNotificationsUnread returns unread notifications query of the authorized user on configured site
+func (so *SlackOverflow) MeNotificationsUnread() (*MeNotificationsUnread, error) {
+	notifications := so.StackExchange.MeNotificationsUnread()
+	err := so.applyParameters(&notifications.Parameters, "notifications", nil)
+	return notifications, err
+}
+
+// Users returns users query on configured site
+func (so *SlackOverflow) Users() (*Users, error) {
+	users := so.StackExchange.Users()
+	err := so.applyParameters(&users.Parameters, "users", nil)
+	return users, err
+}
+
+// TagsTopAnswerers returns top answerers query on configured site
+func (so *SlackOverflow) TagsTopAnswerers() (*TagsTopAnswerers, error) {
+	topAnswerers := so.StackExchange.TagsTopAnswerers()
+	err := so.applyParameters(&topAnswerers.Parameters, "top-answerers", nil)
+	return topAnswerers, err
+}
+
+// TagsInfo returns tags info query on configured site
+func (so *SlackOverflow) TagsInfo() (*TagsInfo, error) {
+	info := so.StackExchange.TagsInfo()
+	err := so.applyParameters(&info.Parameters, "tags", nil)
+	return info, err
+}
+
+// TagsSynonyms returns tag synonyms query on configured site
+func (so *SlackOverflow) TagsSynonyms() (*TagsSynonyms, error) {
+	synonyms := so.StackExchange.TagsSynonyms()
+	err := so.applyParameters(&synonyms.Parameters, "tags", nil)
+	return synonyms, err
+}
+
+// TagsRelated returns related tags query on configured site
+func (so *SlackOverflow) TagsRelated() (*TagsRelated, error) {
+	related := so.StackExchange.TagsRelated()
+	err := so.applyParameters(&related.Parameters, "tags", nil)
+	return related, err
+}
+
+// Pager returns pager limited by max-pages and quota-floor from config
+func (so *SlackOverflow) Pager(fetch PageFetcher) *Pager {
+	pager := NewPager(fetch)
+	if so.Config.StackExchange.MaxPages > 0 {
+		pager.MaxPages = so.Config.StackExchange.MaxPages
+	}
+	pager.QuotaFloor = so.Config.StackExchange.QuotaFloor
+	return pager
+}
+
+// applyParameters sets configured site and parameters, config can
+// override site and refer to filters by name
+func (so *SlackOverflow) applyParameters(p *Parameters, section string, params map[string]string) error {
+	var invalid []string
+	if err := p.Set("site", so.Config.StackExchange.Site); err != nil {
+		invalid = append(invalid, "stackexchange.site: "+err.Error())
+	}
+	var names []string
+	for param := range params {
+		names = append(names, param)
+	}
+	sort.Strings(names)
+	for _, param := range names {
+		value := params[param]
+		if param == "filter" {
+			value = so.Config.StackExchange.ResolveFilter(value)
+		}
+		if err := p.Set(param, value); err != nil {
+			invalid = append(invalid, fmt.Sprintf("stackexchange.%s.%s: %s", section, param, err.Error()))
+		}
+	}
+	if len(invalid) > 0 {
+		return errors.Newf("invalid configuration in %s\n  %s", so.ConfigFilePath.Abs(), strings.Join(invalid, "\n  "))
+	}
+	return nil
+}
 
+// validateStackExchangeConfig rejects unknown and invalid query parameters
+// in config instead of silently ignoring them
+func (so *SlackOverflow) validateStackExchangeConfig() error {
+	if _, err := so.SearchAdvanced(); err != nil {
+		return err
+	}
+	_, err := so.Questions()
 	return err
 }
 
-// SyncQuestion questions
-func (so *SlackOverflow) SyncQuestion(w *cli.Worker, q QuestionObj) {
+// SyncQuestion questions, returns false if question could not be stored
+func (so *SlackOverflow) SyncQuestion(w *cli.Worker, q QuestionObj) bool {
 	var ok string
 	var err error
 	// Create or Update user
@@ -106,11 +385,67 @@ func (so *SlackOverflow) SyncQuestion(w *cli.Worker, q QuestionObj) {
 	} else {
 		w.Log.Ok(ok)
 	}
-	// Create or Update user
-	ok, err = so.DB.SyncStackExchangeQuestion(q, so.Config.StackExchange.Site)
+	// Record title and tag changes of existing question
+	existing := so.DB.FindStackExchangeQuestion(q.QID)
+	if existing.QID > 0 {
+		if edit, changed := NewStackExchangeQuestionEdit(existing, q); changed {
+			edit.Editor = so.StackExchange.QuestionEditor(q.QID, so.Config.StackExchange.Site)
+			so.DryRun.Add("record edit", "", fmt.Sprintf("question %d: %s", q.QID, q.Title))
+			ok, err = so.DB.StackExchangeQuestionEditCreate(edit)
+			if err != nil {
+				w.Log.Error(err)
+			} else {
+				w.Log.Ok(ok)
+			}
+		}
+	}
+	// Create or Update question
+	if existing.QID > 0 && existing.LastActivityDate.Unix() != q.LastActivityDate {
+		so.DryRun.Add("update", "", fmt.Sprintf("question %d: %s", q.QID, q.Title))
+	} else if existing.QID == 0 {
+		so.DryRun.Add("store", "", fmt.Sprintf("question %d: %s", q.QID, q.Title))
+	}
+	ok, err = so.DB.SyncStackExchangeQuestion(q, so.Config.StackExchange.Site, so.TrackedTags())
 	if err != nil {
 		w.Log.Error(err)
-	} else {
-		w.Log.Ok(ok)
+		return false
+	}
+	w.Log.Ok(ok)
+	return true
+}
+
+// SlackSocketMode returns Socket Mode client dispatching to SlackDispatcher
+func (so *SlackOverflow) SlackSocketMode() (*SlackSocketMode, error) {
+	if so.Config.Slack.AppToken == "" {
+		return nil, errors.New("Slack app-level token (app-token) is required for Socket Mode")
+	}
+	return NewSlackSocketMode(so.Config.Slack.APIHost, so.Config.Slack.AppToken, so.SlackDispatcher), nil
+}
+
+// StackExchangeRealtime returns real-time source of new questions on tracked tags
+func (so *SlackOverflow) StackExchangeRealtime() (*StackExchangeRealtime, error) {
+	tags := so.TrackedTags()
+	if len(tags) == 0 {
+		return nil, errors.New("Stack Exchange real-time requires tags in stackexchange.search-advanced.tagged")
+	}
+	siteID := so.Config.StackExchange.RealtimeSiteID
+	if siteID == 0 {
+		if so.Config.StackExchange.Site != "stackoverflow" {
+			return nil, errors.Newf("stackexchange.realtime-site-id is required for site %s", so.Config.StackExchange.Site)
+		}
+		siteID = 1
+	}
+	return NewStackExchangeRealtime(so.Config.StackExchange.RealtimeURL, siteID, tags), nil
+}
+
+// TrackedTags returns configured tags, questions without any of these tags
+// are not relevant anymore
+func (so *SlackOverflow) TrackedTags() []string {
+	var tags []string
+	for _, tag := range strings.Split(so.Config.StackExchange.SearchAdvanced["tagged"], ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
 	}
+	return tags
 }
@@ -0,0 +1,99 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"strings"
+	"sync"
+)
+
+// MaxIDsPerRequest is maximum number of {ids} accepted by Stack Exchange API
+const MaxIDsPerRequest = 100
+
+// BatchIDs splits semicolon delimited ids into batches of at most size ids
+func BatchIDs(ids string, size int) []string {
+	if size <= 0 || size > MaxIDsPerRequest {
+		size = MaxIDsPerRequest
+	}
+	var all []string
+	for _, id := range strings.Split(ids, ";") {
+		if id = strings.TrimSpace(id); id != "" {
+			all = append(all, id)
+		}
+	}
+	var batches []string
+	for len(all) > 0 {
+		n := size
+		if len(all) < n {
+			n = len(all)
+		}
+		batches = append(batches, strings.Join(all[:n], ";"))
+		all = all[n:]
+	}
+	return batches
+}
+
+// QuestionBatch is result of single questions/{ids} batch
+type QuestionBatch struct {
+	IDs   string
+	Items []QuestionObj
+	// Complete is true if all pages of batch were received
+	Complete bool
+	Err      error
+}
+
+// QuestionBatches fetches questions of given ids in batches of 100 with at
+// most workers concurrent requests. Batches are sent to returned channel as
+// they complete, channel is closed when all batches are done. Backoff of any
+// response delays all workers.
+func (so *SlackOverflow) QuestionBatches(ids string, workers int) <-chan QuestionBatch {
+	batches := BatchIDs(ids, MaxIDsPerRequest)
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(batches) {
+		workers = len(batches)
+	}
+
+	jobs := make(chan string)
+	results := make(chan QuestionBatch)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				results <- so.fetchQuestionBatch(batch)
+			}
+		}()
+	}
+	go func() {
+		for _, batch := range batches {
+			jobs <- batch
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+func (so *SlackOverflow) fetchQuestionBatch(ids string) QuestionBatch {
+	result := QuestionBatch{IDs: ids}
+	questions, err := so.Questions()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	pager := so.Pager(questions.Pages(ids))
+	pager.MaxPages = 0
+	for pager.Next() {
+		// Result is reused by next page
+		result.Items = append(result.Items, questions.Result.Items...)
+	}
+	result.Err = pager.Err()
+	result.Complete = pager.Complete()
+	return result
+}
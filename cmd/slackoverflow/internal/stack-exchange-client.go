@@ -5,12 +5,20 @@
 package internal
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/howi-ce/howi/addon/application/plugin/cli"
 )
 
+// DefaultQuestionFilter is filter used for questions unless other is set
+const DefaultQuestionFilter = "!6hYwbNNZ(*eH3a3)XT0aZCOGTo-kwAtAoVF5vC378NPI6Y"
+
 // StackExchangeClient to call Stack Exchange API
 type StackExchangeClient struct {
 	quotaMax       int
@@ -18,28 +26,127 @@ type StackExchangeClient struct {
 	apiHost        string
 	apiVersion     string
 	apiKey         string
+	accessToken    string
+	questionFilter string
+	// mu guards quota and backoff shared by concurrent requests
+	mu           sync.Mutex
+	backoffUntil time.Time
+	ctx          context.Context
+	httpClient   *HTTPClient
 }
 
 // GetQuotaRemaining return remaining quota for today
 func (s *StackExchangeClient) GetQuotaRemaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.quotaRemaining
 }
 
 // GetQuotaMax return maximum allowed quota
 func (s *StackExchangeClient) GetQuotaMax() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.quotaMax
 }
 
 // SetQuotaRemaining set remaining quota for today
 func (s *StackExchangeClient) SetQuotaRemaining(quotaRemaining int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.quotaRemaining = quotaRemaining
 }
 
 // SetQuotaMax set maximum allowed quota
 func (s *StackExchangeClient) SetQuotaMax(quotaMax int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.quotaMax = quotaMax
 }
 
+// SetBackoff delays next requests by given seconds, Stack Exchange
+// API requires clients to respect backoff of any response
+func (s *StackExchangeClient) SetBackoff(seconds int) {
+	if seconds <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if until := time.Now().Add(time.Duration(seconds) * time.Second); until.After(s.backoffUntil) {
+		s.backoffUntil = until
+	}
+}
+
+// setErrorLimits records quota and backoff of failed request, quota is kept
+// as is if response did not include it
+func (s *StackExchangeClient) setErrorLimits(quotaMax int, quotaRemaining int, backoff int) {
+	if quotaMax > 0 {
+		s.SetQuotaMax(quotaMax)
+		s.SetQuotaRemaining(quotaRemaining)
+	}
+	s.SetBackoff(backoff)
+}
+
+// WaitBackoff blocks until backoff set by previous responses has passed or
+// context of client is canceled, in which case request fails right away
+func (s *StackExchangeClient) WaitBackoff() {
+	s.mu.Lock()
+	wait := s.backoffUntil.Sub(time.Now())
+	ctx := s.ctx
+	s.mu.Unlock()
+	if wait <= 0 {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// SetContext of requests, canceling it aborts requests in flight
+func (s *StackExchangeClient) SetContext(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ctx = ctx
+}
+
+// SetTransport of requests, used to record and replay requests
+func (s *StackExchangeClient) SetTransport(rt http.RoundTripper) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.httpClient == nil {
+		s.httpClient = NewHTTPClient()
+	}
+	s.httpClient.SetTransport(rt)
+}
+
+// CloseIdleConnections closes keep-alive connections of the client
+func (s *StackExchangeClient) CloseIdleConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.httpClient != nil {
+		s.httpClient.CloseIdleConnections()
+	}
+}
+
+// httpGet makes request with shared HTTP client
+func (s *StackExchangeClient) httpGet(url string, result interface{}) error {
+	s.mu.Lock()
+	if s.httpClient == nil {
+		s.httpClient = NewHTTPClient()
+	}
+	client, ctx := s.httpClient, s.ctx
+	s.mu.Unlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return client.Get(ctx, url, result)
+}
+
 // GetEndpont returns base endpoint
 func (s *StackExchangeClient) GetEndpont(path string) (*url.URL, error) {
 	return url.Parse(s.apiHost + "/" + s.apiVersion + "/" + path)
@@ -60,6 +167,32 @@ func (s *StackExchangeClient) SetKey(apiKey string) {
 	s.apiKey = apiKey
 }
 
+// SetAccessToken sets OAuth access token passed with all requests
+func (s *StackExchangeClient) SetAccessToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessToken = token
+}
+
+func (s *StackExchangeClient) getAccessToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accessToken
+}
+
+// SetQuestionFilter sets default filter of question endpoints
+func (s *StackExchangeClient) SetQuestionFilter(filter string) {
+	s.questionFilter = filter
+}
+
+// GetQuestionFilter returns default filter of question endpoints
+func (s *StackExchangeClient) GetQuestionFilter() string {
+	if s.questionFilter == "" {
+		return DefaultQuestionFilter
+	}
+	return s.questionFilter
+}
+
 // SearchAdvanced https://api.stackexchange.com/docs/advanced-search
 func (s *StackExchangeClient) SearchAdvanced() *SearchAdvanced {
 	searchAdvanced := &SearchAdvanced{}
@@ -76,6 +209,22 @@ func (s *StackExchangeClient) Questions() *Questions {
 	return questions
 }
 
+// AnswersOnQuestions https://api.stackexchange.com/docs/answers-on-questions
+func (s *StackExchangeClient) AnswersOnQuestions() *AnswersOnQuestions {
+	answers := &AnswersOnQuestions{}
+	answers.Client = s
+	answers.Init()
+	return answers
+}
+
+// PostsRevisions https://api.stackexchange.com/docs/revisions-by-ids
+func (s *StackExchangeClient) PostsRevisions() *PostsRevisions {
+	revisions := &PostsRevisions{}
+	revisions.Client = s
+	revisions.Init()
+	return revisions
+}
+
 // SearchAdvanced - https://api.stackexchange.com/docs/advanced-search
 type SearchAdvanced struct {
 	Client     *StackExchangeClient
@@ -86,64 +235,70 @@ type SearchAdvanced struct {
 
 // Init initializes Search Advanced module
 func (sa *SearchAdvanced) Init() {
-	sa.Parameters.Allow("site", "stackoverflow",
+	sa.Parameters.Allow("site", ParamString, "stackoverflow",
 		"site where to check questions from")
-	sa.Parameters.Allow("q", "",
+	sa.Parameters.Allow("q", ParamString, "",
 		"a free form text parameter, will match all question properties based on an undocumented algorithm.")
-	sa.Parameters.Allow("accepted", "",
+	sa.Parameters.Allow("accepted", ParamBool, "",
 		"true to return only questions with accepted answers, false to return only those without. Omit to elide constraint.")
-	sa.Parameters.Allow("answers", "",
+	sa.Parameters.Allow("answers", ParamInt, "",
 		"the minimum number of answers returned questions must have.")
-	sa.Parameters.Allow("body", "",
+	sa.Parameters.Allow("body", ParamString, "",
 		"text which must appear in returned questions' bodies.")
-	sa.Parameters.Allow("closed", "",
+	sa.Parameters.Allow("closed", ParamBool, "",
 		"true to return only closed questions, false to return only open ones. Omit to elide constraint.")
-	sa.Parameters.Allow("migrated", "",
+	sa.Parameters.Allow("migrated", ParamBool, "",
 		"true to return only questions migrated away from a site, false to return only those not. Omit to elide constraint.")
-	sa.Parameters.Allow("notice", "",
+	sa.Parameters.Allow("notice", ParamBool, "",
 		"true to return only questions with post notices, false to return only those without. Omit to elide constraint.")
-	sa.Parameters.Allow("nottagged", "",
+	sa.Parameters.Allow("nottagged", ParamList, "",
 		"a semicolon delimited list of tags, none of which will be present on returned questions.")
-	sa.Parameters.Allow("tagged", "",
+	sa.Parameters.Allow("tagged", ParamList, "",
 		"a semicolon delimited list of tags, of which at least one will be present on all returned questions.")
-	sa.Parameters.Allow("title", "",
+	sa.Parameters.Allow("title", ParamString, "",
 		"text which must appear in returned questions titles.")
-	sa.Parameters.Allow("user", "",
+	sa.Parameters.Allow("user", ParamInt, "",
 		"the id of the user who must own the questions returned.")
-	sa.Parameters.Allow("url", "",
+	sa.Parameters.Allow("url", ParamString, "",
 		"a url which must be contained in a post, may include a wildcard.")
-	sa.Parameters.Allow("views", "",
+	sa.Parameters.Allow("views", ParamInt, "",
 		"the minimum number of views returned questions must have.")
-	sa.Parameters.Allow("wiki", "",
+	sa.Parameters.Allow("wiki", ParamBool, "",
 		"true to return only community wiki questions, false to return only non-community wiki ones. Omit to elide constraint.")
-	sa.Parameters.Allow("sort", "creation",
-		"The sorts accepted by this method operate on the follow fields of the question object: activity, creation, votes, relevance")
-	sa.Parameters.Allow("order", "asc",
-		"Order results is ascending or descending")
-	sa.Parameters.Allow("fromdate", "",
+	sa.Parameters.AllowEnum("sort", "creation",
+		"The sorts accepted by this method operate on the follow fields of the question object: activity, creation, votes, relevance",
+		"activity", "creation", "votes", "relevance")
+	sa.Parameters.AllowEnum("order", "asc",
+		"Order results is ascending or descending",
+		"asc", "desc")
+	sa.Parameters.Allow("fromdate", ParamDate, "",
 		"From which date to search")
-	sa.Parameters.Allow("todate", "",
+	sa.Parameters.Allow("todate", ParamDate, "",
 		"Up to which date to search")
-	sa.Parameters.Allow("filter", "!6hYwbNNZ(*eH3a3)XT0aZCOGTo-kwAtAoVF5vC378NPI6Y",
+	sa.Parameters.Allow("filter", ParamString, sa.Client.GetQuestionFilter(),
 		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
-	sa.Parameters.Allow("pagesize", 100,
+	sa.Parameters.Allow("pagesize", ParamInt, 100,
 		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
-	sa.Parameters.Allow("page", 1,
+	sa.Parameters.Allow("page", ParamInt, 1,
 		"Current page to be fetched")
-	sa.Parameters.Allow("key", sa.Client.apiKey,
+	sa.Parameters.Allow("key", ParamString, sa.Client.apiKey,
 		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	sa.Parameters.Allow("access_token", ParamString, sa.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
 }
 
 // DrawQuery output table of search query
 func (sa *SearchAdvanced) DrawQuery(w *cli.Worker) {
 	w.Log.Line("Search Advanced query will be performed with following parameters")
-	query := NewTable("parameter", "defined value", "default", "description")
-	for key, allowed := range sa.Parameters.GetAllowed() {
-		query.AddRow(key, sa.Parameters.ValueOf(key), allowed.String(), allowed.Decription())
+	query := NewTable("parameter", "type", "defined value", "default", "description")
+	allowed := sa.Parameters.GetAllowed()
+	for _, key := range sa.Parameters.AllowedNames() {
+		param := allowed[key]
+		query.AddRow(key, param.TypeInfo(), redactParam(key, sa.Parameters.ValueOf(key)), redactParam(key, param.String()), param.Decription())
 	}
 	query.Print()
 	url, _ := sa.GetURL()
-	w.Log.Linef("Resulting Url: %s", url)
+	w.Log.Linef("Resulting Url: %s", redactRawURL(url))
 }
 
 // GetURL composed from current parameters
@@ -158,6 +313,7 @@ func (sa *SearchAdvanced) GetURL() (string, error) {
 	for param, value := range sa.Parameters.GetApplied() {
 		query.Set(param, value.String())
 	}
+	query.Set("page", fmt.Sprintf("%d", sa.GetCurrentPageNr()))
 
 	endpoint.RawQuery = query.Encode()
 
@@ -173,16 +329,21 @@ func (sa *SearchAdvanced) Get() (bool, error) {
 		return false, err
 	}
 
-	err = HTTPGetByURL(url, &sa.Result)
+	sa.Client.WaitBackoff()
+	result := new(QuestionsWrapperObj)
+	err = sa.Client.httpGet(url, result)
 	if err != nil {
-		fmt.Println(err.Error())
+		// API error responses carry quota and backoff too
+		sa.Client.setErrorLimits(result.QuotaMax, result.QuotaRemaining, result.Backoff)
 		return false, err
 	}
+	sa.Result = result
 
 	sa.Paging.curentPage = sa.Result.Page
 	sa.Paging.hasMore = sa.Result.HasMore
 	sa.Client.SetQuotaMax(sa.Result.QuotaMax)
 	sa.Client.SetQuotaRemaining(sa.Result.QuotaRemaining)
+	sa.Client.SetBackoff(sa.Result.Backoff)
 
 	return true, err
 }
@@ -197,38 +358,44 @@ type Questions struct {
 
 // Init initializes Questions module
 func (q *Questions) Init() {
-	q.Parameters.Allow("site", "stackoverflow",
+	q.Parameters.Allow("site", ParamString, "stackoverflow",
 		"site where to check questions from")
 
-	q.Parameters.Allow("sort", "activity",
-		"The sorts accepted by this method operate on the follow fields of the question object: activity, creation, votes")
-	q.Parameters.Allow("order", "asc",
-		"Order results is ascending or descending")
-	q.Parameters.Allow("fromdate", "",
+	q.Parameters.AllowEnum("sort", "activity",
+		"The sorts accepted by this method operate on the follow fields of the question object: activity, creation, votes",
+		"activity", "creation", "votes")
+	q.Parameters.AllowEnum("order", "asc",
+		"Order results is ascending or descending",
+		"asc", "desc")
+	q.Parameters.Allow("fromdate", ParamDate, "",
 		"From which date to search")
-	q.Parameters.Allow("todate", "",
+	q.Parameters.Allow("todate", ParamDate, "",
 		"Up to which date to search")
 
-	q.Parameters.Allow("filter", "!6hYwbNNZ(*eH3a3)XT0aZCOGTo-kwAtAoVF5vC378NPI6Y",
+	q.Parameters.Allow("filter", ParamString, q.Client.GetQuestionFilter(),
 		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
-	q.Parameters.Allow("pagesize", 100,
+	q.Parameters.Allow("pagesize", ParamInt, 100,
 		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
-	q.Parameters.Allow("page", 1,
+	q.Parameters.Allow("page", ParamInt, 1,
 		"Current page to be fetched")
-	q.Parameters.Allow("min", "",
+	q.Parameters.Allow("min", ParamString, "",
 		"min and max specify the range of a field must fall in (that field being specified by sort)")
-	q.Parameters.Allow("max", "",
+	q.Parameters.Allow("max", ParamString, "",
 		"min and max specify the range of a field must fall in (that field being specified by sort)")
-	q.Parameters.Allow("key", q.Client.apiKey,
+	q.Parameters.Allow("key", ParamString, q.Client.apiKey,
 		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	q.Parameters.Allow("access_token", ParamString, q.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
 }
 
 // DrawQuery output table of questions query
 func (q *Questions) DrawQuery(w *cli.Worker, ids string) {
 	w.Log.Line("Questions query will be performed with following parameters")
-	query := NewTable("parameter", "defined value", "default", "description")
-	for key, allowed := range q.Parameters.GetAllowed() {
-		query.AddRow(key, q.Parameters.ValueOf(key), allowed.String(), allowed.Decription())
+	query := NewTable("parameter", "type", "defined value", "default", "description")
+	allowed := q.Parameters.GetAllowed()
+	for _, key := range q.Parameters.AllowedNames() {
+		param := allowed[key]
+		query.AddRow(key, param.TypeInfo(), redactParam(key, q.Parameters.ValueOf(key)), redactParam(key, param.String()), param.Decription())
 	}
 	var idss string
 	if len(ids) > 10 {
@@ -236,11 +403,11 @@ func (q *Questions) DrawQuery(w *cli.Worker, ids string) {
 	} else {
 		idss = ids
 	}
-	query.AddRow("ids", idss+"...", "",
+	query.AddRow("ids", "list(a;b)", idss+"...", "",
 		"{ids} can contain up to 100 semicolon delimited ids, to find ids programatically look for question_id ")
 	query.Print()
 	url, _ := q.GetURL(ids)
-	w.Log.Linef("Resulting Url: %s", url)
+	w.Log.Linef("Resulting Url: %s", redactRawURL(url))
 }
 
 // Get request
@@ -252,16 +419,21 @@ func (q *Questions) Get(ids string) (bool, error) {
 		return false, err
 	}
 
-	err = HTTPGetByURL(url, &q.Result)
+	q.Client.WaitBackoff()
+	result := new(QuestionsWrapperObj)
+	err = q.Client.httpGet(url, result)
 	if err != nil {
-		fmt.Println(err.Error())
+		// API error responses carry quota and backoff too
+		q.Client.setErrorLimits(result.QuotaMax, result.QuotaRemaining, result.Backoff)
 		return false, err
 	}
+	q.Result = result
 
 	q.Paging.curentPage = q.Result.Page
 	q.Paging.hasMore = q.Result.HasMore
 	q.Client.SetQuotaMax(q.Result.QuotaMax)
 	q.Client.SetQuotaRemaining(q.Result.QuotaRemaining)
+	q.Client.SetBackoff(q.Result.Backoff)
 
 	return true, err
 }
@@ -286,12 +458,211 @@ func (q *Questions) GetURL(ids string) (string, error) {
 	for param, value := range q.Parameters.GetApplied() {
 		query.Set(param, value.String())
 	}
+	query.Set("page", fmt.Sprintf("%d", q.GetCurrentPageNr()))
+
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String(), err
+}
+
+// AnswersOnQuestions - https://api.stackexchange.com/docs/answers-on-questions
+type AnswersOnQuestions struct {
+	Client     *StackExchangeClient
+	Parameters Parameters
+	Paging
+	Result *AnswersWrapperObj
+}
+
+// Init initializes Answers on Questions module
+func (a *AnswersOnQuestions) Init() {
+	a.Parameters.Allow("site", ParamString, "stackoverflow",
+		"site where to check answers from")
+	a.Parameters.AllowEnum("sort", "creation",
+		"The sorts accepted by this method operate on the follow fields of the answer object: activity, creation, votes",
+		"activity", "creation", "votes")
+	a.Parameters.AllowEnum("order", "desc",
+		"Order results is ascending or descending",
+		"asc", "desc")
+	a.Parameters.Allow("filter", ParamString, "default",
+		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
+	a.Parameters.Allow("pagesize", ParamInt, 100,
+		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
+	a.Parameters.Allow("page", ParamInt, 1,
+		"Current page to be fetched")
+	a.Parameters.Allow("key", ParamString, a.Client.apiKey,
+		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	a.Parameters.Allow("access_token", ParamString, a.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
+}
+
+// Get request
+func (a *AnswersOnQuestions) Get(ids string) (bool, error) {
+
+	url, err := a.GetURL(ids)
+
+	if err != nil {
+		return false, err
+	}
+
+	a.Client.WaitBackoff()
+	result := new(AnswersWrapperObj)
+	err = a.Client.httpGet(url, result)
+	if err != nil {
+		// API error responses carry quota and backoff too
+		a.Client.setErrorLimits(result.QuotaMax, result.QuotaRemaining, result.Backoff)
+		return false, err
+	}
+	a.Result = result
+
+	a.Paging.curentPage = a.Result.Page
+	a.Paging.hasMore = a.Result.HasMore
+	a.Client.SetQuotaMax(a.Result.QuotaMax)
+	a.Client.SetQuotaRemaining(a.Result.QuotaRemaining)
+	a.Client.SetBackoff(a.Result.Backoff)
+
+	return true, err
+}
+
+// GetURL composed from current parameters
+func (a *AnswersOnQuestions) GetURL(ids string) (string, error) {
+	// Apply default parameters
+	a.Parameters.ApplyDefaults()
+
+	// Make sure to set failing id if no ids are supplied
+	if len(ids) == 0 {
+		ids = "100"
+	}
+
+	endpoint, err := a.Client.GetEndpont("questions/" + ids + "/answers")
+	if err != nil {
+		return "", err
+	}
+	query := endpoint.Query()
+
+	// Apply defined parameters
+	for param, value := range a.Parameters.GetApplied() {
+		query.Set(param, value.String())
+	}
+	query.Set("page", fmt.Sprintf("%d", a.GetCurrentPageNr()))
 
 	endpoint.RawQuery = query.Encode()
 
 	return endpoint.String(), err
 }
 
+// PostsRevisions - https://api.stackexchange.com/docs/revisions-by-ids
+type PostsRevisions struct {
+	Client     *StackExchangeClient
+	Parameters Parameters
+	Paging
+	Result *RevisionsWrapperObj
+}
+
+// Init initializes Posts Revisions module
+func (r *PostsRevisions) Init() {
+	r.Parameters.Allow("site", ParamString, "stackoverflow",
+		"site where to check revisions from")
+	r.Parameters.Allow("fromdate", ParamDate, "",
+		"From which date to search")
+	r.Parameters.Allow("todate", ParamDate, "",
+		"Up to which date to search")
+	r.Parameters.Allow("filter", ParamString, "default",
+		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
+	r.Parameters.Allow("pagesize", ParamInt, 100,
+		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
+	r.Parameters.Allow("page", ParamInt, 1,
+		"Current page to be fetched")
+	r.Parameters.Allow("key", ParamString, r.Client.apiKey,
+		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	r.Parameters.Allow("access_token", ParamString, r.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
+}
+
+// Get request
+func (r *PostsRevisions) Get(ids string) (bool, error) {
+
+	endpoint, err := r.Client.GetEndpont("posts/" + ids + "/revisions")
+	if err != nil {
+		return false, err
+	}
+	r.Parameters.ApplyDefaults()
+	query := endpoint.Query()
+	for param, value := range r.Parameters.GetApplied() {
+		query.Set(param, value.String())
+	}
+	query.Set("page", fmt.Sprintf("%d", r.GetCurrentPageNr()))
+	endpoint.RawQuery = query.Encode()
+
+	r.Client.WaitBackoff()
+	result := new(RevisionsWrapperObj)
+	err = r.Client.httpGet(endpoint.String(), result)
+	if err != nil {
+		// API error responses carry quota and backoff too
+		r.Client.setErrorLimits(result.QuotaMax, result.QuotaRemaining, result.Backoff)
+		return false, err
+	}
+	r.Result = result
+
+	r.Paging.curentPage = r.Result.Page
+	r.Paging.hasMore = r.Result.HasMore
+	r.Client.SetQuotaMax(r.Result.QuotaMax)
+	r.Client.SetQuotaRemaining(r.Result.QuotaRemaining)
+	r.Client.SetBackoff(r.Result.Backoff)
+
+	return true, err
+}
+
+// RevisionsWrapperObj is a API response type
+type RevisionsWrapperObj struct {
+	Backoff        int           `json:"backoff"`
+	ErrorID        int           `json:"error_id"`
+	ErrorName      string        `json:"error_name"`
+	ErrorMessage   string        `json:"error_message"`
+	HasMore        bool          `json:"has_more"`
+	Page           int           `json:"page"`
+	QuotaMax       int           `json:"quota_max"`
+	QuotaRemaining int           `json:"quota_remaining"`
+	Items          []RevisionObj `json:"items"`
+}
+
+// RevisionObj is post revision returned by StackExchange API
+type RevisionObj struct {
+	PostID         int            `json:"post_id"`
+	RevisionNumber int            `json:"revision_number"`
+	RevisionType   string         `json:"revision_type"`
+	CreationDate   int64          `json:"creation_date"`
+	Comment        string         `json:"comment"`
+	Title          string         `json:"title"`
+	LastTitle      string         `json:"last_title"`
+	Tags           []string       `json:"tags"`
+	LastTags       []string       `json:"last_tags"`
+	User           ShallowUserObj `json:"user"`
+}
+
+// AnswersWrapperObj is a API response type
+type AnswersWrapperObj struct {
+	Backoff        int         `json:"backoff"`
+	ErrorID        int         `json:"error_id"`
+	ErrorName      string      `json:"error_name"`
+	ErrorMessage   string      `json:"error_message"`
+	HasMore        bool        `json:"has_more"`
+	Page           int         `json:"page"`
+	QuotaMax       int         `json:"quota_max"`
+	QuotaRemaining int         `json:"quota_remaining"`
+	Items          []AnswerObj `json:"items"`
+}
+
+// AnswerObj is answer item returned by StackExchange API
+type AnswerObj struct {
+	AID              int            `json:"answer_id"`
+	QID              int            `json:"question_id"`
+	Owner            ShallowUserObj `json:"owner"`
+	IsAccepted       bool           `json:"is_accepted"`
+	Score            int            `json:"score"`
+	CreationDate     int64          `json:"creation_date"`
+	LastActivityDate int64          `json:"last_activity_date"`
+}
+
 // QuestionsWrapperObj is a API response type
 type QuestionsWrapperObj struct {
 	Backoff        int           `json:"backoff"`
@@ -313,8 +684,11 @@ type QuestionObj struct {
 	LastActivityDate int64          `json:"last_activity_date"`
 	Owner            ShallowUserObj `json:"owner"`
 	IsAnswered       bool           `json:"is_answered"`
+	AcceptedAnswerID int            `json:"accepted_answer_id"`
 	ShareLink        string         `json:"share_link"`
 	ClosedReason     string         `json:"closed_reason"`
+	ClosedDate       int64          `json:"closed_date"`
+	MigratedTo       *MigrationObj  `json:"migrated_to"`
 	Tags             []string       `json:"tags"`
 	Score            int            `json:"score"`
 	ViewCount        int            `json:"view_count"`
@@ -325,119 +699,69 @@ type QuestionObj struct {
 	DeleteVoteCount  int            `json:"delete_vote_count"`
 	FavoriteCount    int            `json:"favorite_count"`
 	ReOpenVoteCount  int            `json:"reopen_vote_count"`
+	BodyMarkdown     string         `json:"body_markdown"`
 }
 
-// BadgeCountsObj of Stack Exchange User
-type BadgeCountsObj struct {
-	Bronze int `json:"bronze"`
-	Silver int `json:"silver"`
-	Gold   int `json:"gold"`
-}
-
-// ShallowUserObj returned by Stack Exchange API
-type ShallowUserObj struct {
-	UID          int            `json:"user_id"`
-	Reputation   int            `json:"reputation"`
-	ProfileImage string         `json:"profile_image"`
-	DisplayName  string         `json:"display_name"`
-	Link         string         `json:"link"`
-	AcceptRate   int            `json:"accept_rate"`
-	BadgeCounts  BadgeCountsObj `json:"badge_counts"`
-}
-
-// Parameters Stack Exchange query parameters
-type Parameters struct {
-	applied map[string]Parameter
-	allowed map[string]Parameter
-}
-
-// Allow parameter to be set
-func (p *Parameters) Allow(param string, value interface{}, desc string) {
-	if p.allowed == nil {
-		p.allowed = make(map[string]Parameter)
-	}
-	p.allowed[param] = Parameter{param, value, desc}
-}
-
-// IsSet return true is parameter is set
-func (p *Parameters) IsSet(param string) bool {
-	if _, ok := p.applied[param]; ok {
-		return true
+// Lifecycle returns lifecycle state of question and link to migrated question
+func (q *QuestionObj) Lifecycle() (string, string) {
+	if q.MigratedTo != nil {
+		return LifecycleMigrated, q.MigratedTo.Link()
 	}
-	return false
-}
-
-// Set adds or moifies a parameter given by the key and value
-func (p *Parameters) Set(param string, value interface{}) {
-	if !p.IsAllowed(param) || value == "" {
-		return
-	}
-	if p.applied == nil {
-		p.applied = make(map[string]Parameter)
-	}
-	p.applied[param] = Parameter{param, value, ""}
-}
-
-// ValueOf returns value of given parameter if it is set
-func (p *Parameters) ValueOf(param string) string {
-	if val, ok := p.applied[param]; ok {
-		return val.String()
+	if q.ClosedReason != "" || q.ClosedDate > 0 {
+		return LifecycleClosed, ""
 	}
-	return ""
-}
-
-// Delete the given parameter
-func (p *Parameters) Delete(param string) {
-	delete(p.applied, param)
+	return LifecycleOpen, ""
 }
 
-// IsAllowed return true is parameter is allowed by this endpoint
-func (p *Parameters) IsAllowed(param string) bool {
-	if _, ok := p.allowed[param]; ok {
-		return true
+// HasAnyTag returns true if question has at least one of given tags
+func (q *QuestionObj) HasAnyTag(tags []string) bool {
+	for _, tag := range q.Tags {
+		for _, t := range tags {
+			if strings.EqualFold(tag, t) {
+				return true
+			}
+		}
 	}
 	return false
 }
 
-// GetAllowed returns parameters accepted by this endpoint
-func (p *Parameters) GetAllowed() map[string]Parameter {
-	return p.allowed
+// MigrationObj is migration info of question
+type MigrationObj struct {
+	QID       int     `json:"question_id"`
+	OtherSite SiteObj `json:"other_site"`
 }
 
-// ApplyDefaults apply default parameters
-func (p *Parameters) ApplyDefaults() {
-	// Do nothing if no defaults are set
-	if p.allowed == nil {
-		return
-	}
-	for param, value := range p.GetAllowed() {
-		// Set default value if parameter has not been set
-		if !p.IsSet(param) {
-			p.Set(param, value.String())
-		}
+// Link to migrated question
+func (m *MigrationObj) Link() string {
+	if m.OtherSite.SiteURL == "" {
+		return m.OtherSite.Name
 	}
+	return fmt.Sprintf("%s/q/%d", m.OtherSite.SiteURL, m.QID)
 }
 
-// GetApplied returns parameters which have been set
-func (p *Parameters) GetApplied() map[string]Parameter {
-	return p.applied
-}
-
-// Parameter Stack Exchange parameter
-type Parameter struct {
-	param       string
-	value       interface{}
-	description string
+// SiteObj is Stack Exchange site
+type SiteObj struct {
+	Name             string `json:"name"`
+	SiteURL          string `json:"site_url"`
+	APISiteParameter string `json:"api_site_parameter"`
 }
 
-// String value of the parameter
-func (p *Parameter) String() string {
-	return fmt.Sprintf("%v", p.value)
+// BadgeCountsObj of Stack Exchange User
+type BadgeCountsObj struct {
+	Bronze int `json:"bronze"`
+	Silver int `json:"silver"`
+	Gold   int `json:"gold"`
 }
 
-// Decription of this parameter
-func (p *Parameter) Decription() string {
-	return p.description
+// ShallowUserObj returned by Stack Exchange API
+type ShallowUserObj struct {
+	UID          int            `json:"user_id"`
+	Reputation   int            `json:"reputation"`
+	ProfileImage string         `json:"profile_image"`
+	DisplayName  string         `json:"display_name"`
+	Link         string         `json:"link"`
+	AcceptRate   int            `json:"accept_rate"`
+	BadgeCounts  BadgeCountsObj `json:"badge_counts"`
 }
 
 // Paging - https://api.stackexchange.com/docs/paging
@@ -0,0 +1,69 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQueryErrorKeepsQuotaAndBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error_id":502,"error_name":"throttle_violation","error_message":"too many requests","backoff":30,"quota_max":300,"quota_remaining":0}`))
+	}))
+	defer server.Close()
+
+	client := &StackExchangeClient{}
+	client.SetHost(server.URL)
+	client.SetAPIVersion("2.2")
+	client.SetQuotaMax(10000)
+	client.SetQuotaRemaining(100)
+	queries := map[string]func() (bool, error){
+		"search/advanced": func() (bool, error) { return client.SearchAdvanced().Get() },
+		"questions":       func() (bool, error) { return client.Questions().Get("1") },
+	}
+	for name, get := range queries {
+		t.Run(name, func(t *testing.T) {
+			client.backoffUntil = time.Time{}
+			ok, err := get()
+			if ok || err == nil {
+				t.Fatal("error response was not returned as error")
+			}
+			if _, isAPIError := err.(*APIError); !isAPIError {
+				t.Errorf("error %T, want *APIError", err)
+			}
+			if max, remaining := client.GetQuotaMax(), client.GetQuotaRemaining(); max != 300 || remaining != 0 {
+				t.Errorf("quota %d/%d, want 0/300", remaining, max)
+			}
+			if wait := client.backoffUntil.Sub(time.Now()); wait < 29*time.Second {
+				t.Errorf("backoff of %s, want 30s", wait)
+			}
+		})
+	}
+}
+
+func TestWaitBackoffCanceled(t *testing.T) {
+	client := &StackExchangeClient{}
+	ctx, cancel := context.WithCancel(context.Background())
+	client.SetContext(ctx)
+	client.SetBackoff(60)
+
+	done := make(chan struct{})
+	go func() {
+		client.WaitBackoff()
+		close(done)
+	}()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitBackoff did not return when context was canceled")
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/howi-ce/howi/std/errors"
+)
+
+// FiltersWrapperObj is a API response type
+type FiltersWrapperObj struct {
+	Backoff        int         `json:"backoff"`
+	ErrorID        int         `json:"error_id"`
+	ErrorName      string      `json:"error_name"`
+	ErrorMessage   string      `json:"error_message"`
+	HasMore        bool        `json:"has_more"`
+	QuotaMax       int         `json:"quota_max"`
+	QuotaRemaining int         `json:"quota_remaining"`
+	Items          []FilterObj `json:"items"`
+}
+
+// FilterObj is filter returned by StackExchange API
+type FilterObj struct {
+	Filter         string   `json:"filter"`
+	FilterType     string   `json:"filter_type"`
+	IncludedFields []string `json:"included_fields"`
+}
+
+// QuestionFilterPrefix is name prefix of automatically created question filters
+const QuestionFilterPrefix = "questions-"
+
+// filterTypes maps response objects to Stack Exchange type names used in filters
+var filterTypes = map[reflect.Type]string{
+	reflect.TypeOf(QuestionsWrapperObj{}): "",
+	reflect.TypeOf(QuestionObj{}):         "question",
+	reflect.TypeOf(ShallowUserObj{}):      "shallow_user",
+	reflect.TypeOf(BadgeCountsObj{}):      "badge_count",
+	reflect.TypeOf(MigrationObj{}):        "migration_info",
+	reflect.TypeOf(SiteObj{}):             "site",
+}
+
+// FiltersCreate https://api.stackexchange.com/docs/create-filter
+// creates filter from base filter including and excluding given fields
+func (s *StackExchangeClient) FiltersCreate(base string, include []string, exclude []string) (FilterObj, error) {
+	query := url.Values{}
+	if base != "" {
+		query.Set("base", base)
+	}
+	if len(include) > 0 {
+		query.Set("include", strings.Join(include, ";"))
+	}
+	if len(exclude) > 0 {
+		query.Set("exclude", strings.Join(exclude, ";"))
+	}
+	query.Set("unsafe", "false")
+	filters, err := s.filters("filters/create", query)
+	if err != nil {
+		return FilterObj{}, err
+	}
+	if len(filters) == 0 {
+		return FilterObj{}, errors.New("Stack Exchange: filters/create returned no filter")
+	}
+	return filters[0], nil
+}
+
+// FiltersRead https://api.stackexchange.com/docs/read-filter
+// decodes given filters into included fields
+func (s *StackExchangeClient) FiltersRead(filters ...string) ([]FilterObj, error) {
+	if len(filters) == 0 {
+		return nil, errors.New("Stack Exchange: no filters to read")
+	}
+	var escaped []string
+	for _, filter := range filters {
+		escaped = append(escaped, url.PathEscape(filter))
+	}
+	return s.filters("filters/"+strings.Join(escaped, ";"), url.Values{})
+}
+
+func (s *StackExchangeClient) filters(path string, query url.Values) ([]FilterObj, error) {
+	endpoint, err := s.GetEndpont(path)
+	if err != nil {
+		return nil, err
+	}
+	if s.apiKey != "" {
+		query.Set("key", s.apiKey)
+	}
+	if token := s.getAccessToken(); token != "" {
+		query.Set("access_token", token)
+	}
+	endpoint.RawQuery = query.Encode()
+
+	var result FiltersWrapperObj
+	s.WaitBackoff()
+	if err = s.httpGet(endpoint.String(), &result); err != nil {
+		return nil, err
+	}
+	s.SetBackoff(result.Backoff)
+	if err = apiError(result.ErrorID, result.ErrorName, result.ErrorMessage); err != nil {
+		return nil, err
+	}
+	s.SetQuotaMax(result.QuotaMax)
+	s.SetQuotaRemaining(result.QuotaRemaining)
+	return result.Items, nil
+}
+
+// QuestionFilterFields returns fields QuestionsWrapperObj decodes,
+// question body is included only if withBody is true
+func QuestionFilterFields(withBody bool) []string {
+	var exclude []string
+	if !withBody {
+		exclude = append(exclude, "question.body_markdown")
+	}
+	return FilterFields(QuestionsWrapperObj{}, exclude...)
+}
+
+// FilterFields returns sorted filter fields of given response object and its
+// nested objects based on json tags, wrapper fields are prefixed with "."
+func FilterFields(obj interface{}, exclude ...string) []string {
+	fields := make(map[string]bool)
+	for _, field := range exclude {
+		fields[field] = false
+	}
+	collectFilterFields(reflect.TypeOf(obj), fields)
+	var list []string
+	for field, include := range fields {
+		if include {
+			list = append(list, field)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+func collectFilterFields(t reflect.Type, fields map[string]bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	typeName, ok := filterTypes[t]
+	if !ok {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		field := typeName + "." + name
+		if _, seen := fields[field]; seen {
+			continue
+		}
+		fields[field] = true
+		collectFilterFields(t.Field(i).Type, fields)
+	}
+}
+
+// FilterName returns name for filter of given fields, name changes when
+// fields change so that new filter gets created
+func FilterName(prefix string, fields []string) string {
+	h := fnv.New32a()
+	h.Write([]byte(strings.Join(fields, ";")))
+	return fmt.Sprintf("%s%08x", prefix, h.Sum32())
+}
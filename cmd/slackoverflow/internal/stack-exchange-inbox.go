@@ -0,0 +1,264 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// InboxSourceInbox items are from /me/inbox
+	InboxSourceInbox = "inbox"
+	// InboxSourceNotification items are from /me/notifications
+	InboxSourceNotification = "notification"
+)
+
+// MeInboxUnread https://api.stackexchange.com/docs/me-unread-inbox
+func (s *StackExchangeClient) MeInboxUnread() *MeInboxUnread {
+	inbox := &MeInboxUnread{}
+	inbox.Client = s
+	inbox.Init()
+	return inbox
+}
+
+// MeNotificationsUnread https://api.stackexchange.com/docs/me-unread-notifications
+func (s *StackExchangeClient) MeNotificationsUnread() *MeNotificationsUnread {
+	notifications := &MeNotificationsUnread{}
+	notifications.Client = s
+	notifications.Init()
+	return notifications
+}
+
+// MeInboxUnread - https://api.stackexchange.com/docs/me-unread-inbox
+// Requires access token with read_inbox scope.
+type MeInboxUnread struct {
+	Client     *StackExchangeClient
+	Parameters Parameters
+	Paging
+	Result *InboxWrapperObj
+}
+
+// Init initializes Me Inbox Unread module
+func (i *MeInboxUnread) Init() {
+	i.Parameters.Allow("site", ParamString, "stackoverflow",
+		"site of the user whose inbox is read")
+	i.Parameters.Allow("since", ParamDate, "",
+		"only items created after this date are returned")
+	i.Parameters.Allow("filter", ParamString, "withbody",
+		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
+	i.Parameters.Allow("pagesize", ParamInt, 100,
+		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
+	i.Parameters.Allow("page", ParamInt, 1,
+		"Current page to be fetched")
+	i.Parameters.Allow("key", ParamString, i.Client.apiKey,
+		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	i.Parameters.Allow("access_token", ParamString, i.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
+}
+
+// Get request
+func (i *MeInboxUnread) Get() (bool, error) {
+	endpoint, err := i.Client.GetEndpont("me/inbox/unread")
+	if err != nil {
+		return false, err
+	}
+	i.Parameters.ApplyDefaults()
+	query := endpoint.Query()
+	for param, value := range i.Parameters.GetApplied() {
+		query.Set(param, value.String())
+	}
+	query.Set("page", fmt.Sprintf("%d", i.GetCurrentPageNr()))
+	endpoint.RawQuery = query.Encode()
+
+	i.Client.WaitBackoff()
+	err = i.Client.httpGet(endpoint.String(), &i.Result)
+	if err != nil {
+		return false, err
+	}
+
+	i.Paging.curentPage = i.Result.Page
+	i.Paging.hasMore = i.Result.HasMore
+	i.Client.SetQuotaMax(i.Result.QuotaMax)
+	i.Client.SetQuotaRemaining(i.Result.QuotaRemaining)
+	i.Client.SetBackoff(i.Result.Backoff)
+
+	return true, err
+}
+
+// Pages returns fetcher for Pager
+func (i *MeInboxUnread) Pages() PageFetcher {
+	return func(page int) (PageInfo, error) {
+		i.SetPage(page)
+		_, err := i.Get()
+		return pageInfo(i.Result, err)
+	}
+}
+
+// MeNotificationsUnread - https://api.stackexchange.com/docs/me-unread-notifications
+// Requires access token with read_inbox scope.
+type MeNotificationsUnread struct {
+	Client     *StackExchangeClient
+	Parameters Parameters
+	Paging
+	Result *NotificationsWrapperObj
+}
+
+// Init initializes Me Notifications Unread module
+func (n *MeNotificationsUnread) Init() {
+	n.Parameters.Allow("site", ParamString, "stackoverflow",
+		"site of the user whose notifications are read")
+	n.Parameters.Allow("filter", ParamString, "default",
+		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
+	n.Parameters.Allow("pagesize", ParamInt, 100,
+		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
+	n.Parameters.Allow("page", ParamInt, 1,
+		"Current page to be fetched")
+	n.Parameters.Allow("key", ParamString, n.Client.apiKey,
+		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	n.Parameters.Allow("access_token", ParamString, n.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
+}
+
+// Get request
+func (n *MeNotificationsUnread) Get() (bool, error) {
+	endpoint, err := n.Client.GetEndpont("me/notifications/unread")
+	if err != nil {
+		return false, err
+	}
+	n.Parameters.ApplyDefaults()
+	query := endpoint.Query()
+	for param, value := range n.Parameters.GetApplied() {
+		query.Set(param, value.String())
+	}
+	query.Set("page", fmt.Sprintf("%d", n.GetCurrentPageNr()))
+	endpoint.RawQuery = query.Encode()
+
+	n.Client.WaitBackoff()
+	err = n.Client.httpGet(endpoint.String(), &n.Result)
+	if err != nil {
+		return false, err
+	}
+
+	n.Paging.curentPage = n.Result.Page
+	n.Paging.hasMore = n.Result.HasMore
+	n.Client.SetQuotaMax(n.Result.QuotaMax)
+	n.Client.SetQuotaRemaining(n.Result.QuotaRemaining)
+	n.Client.SetBackoff(n.Result.Backoff)
+
+	return true, err
+}
+
+// Pages returns fetcher for Pager
+func (n *MeNotificationsUnread) Pages() PageFetcher {
+	return func(page int) (PageInfo, error) {
+		n.SetPage(page)
+		_, err := n.Get()
+		return pageInfo(n.Result, err)
+	}
+}
+
+// InboxWrapperObj is a API response type
+type InboxWrapperObj struct {
+	Backoff        int            `json:"backoff"`
+	ErrorID        int            `json:"error_id"`
+	ErrorName      string         `json:"error_name"`
+	ErrorMessage   string         `json:"error_message"`
+	HasMore        bool           `json:"has_more"`
+	Page           int            `json:"page"`
+	QuotaMax       int            `json:"quota_max"`
+	QuotaRemaining int            `json:"quota_remaining"`
+	Items          []InboxItemObj `json:"items"`
+}
+
+// InboxItemObj is inbox item returned by StackExchange API
+// item_type is one of comment, chat_message, new_answer, careers_message,
+// careers_invitations, meta_question, post_notice or moderator_message
+type InboxItemObj struct {
+	ItemType     string  `json:"item_type"`
+	QID          int     `json:"question_id"`
+	AID          int     `json:"answer_id"`
+	CommentID    int     `json:"comment_id"`
+	Title        string  `json:"title"`
+	CreationDate int64   `json:"creation_date"`
+	IsUnread     bool    `json:"is_unread"`
+	Site         SiteObj `json:"site"`
+	Body         string  `json:"body"`
+	Link         string  `json:"link"`
+}
+
+// InboxItem returns database record of inbox item
+func (i *InboxItemObj) InboxItem() StackExchangeInboxItem {
+	return StackExchangeInboxItem{
+		Key: fmt.Sprintf("%s:%s:%d:%d:%d:%d", InboxSourceInbox, i.ItemType,
+			i.QID, i.AID, i.CommentID, i.CreationDate),
+		Source:   InboxSourceInbox,
+		ItemType: i.ItemType,
+		Title:    i.Title,
+		Link:     i.Link,
+		Body:     i.Body,
+		Site:     i.Site.Name,
+		Created:  time.Unix(i.CreationDate, 0).UTC(),
+	}
+}
+
+// NotificationsWrapperObj is a API response type
+type NotificationsWrapperObj struct {
+	Backoff        int               `json:"backoff"`
+	ErrorID        int               `json:"error_id"`
+	ErrorName      string            `json:"error_name"`
+	ErrorMessage   string            `json:"error_message"`
+	HasMore        bool              `json:"has_more"`
+	Page           int               `json:"page"`
+	QuotaMax       int               `json:"quota_max"`
+	QuotaRemaining int               `json:"quota_remaining"`
+	Items          []NotificationObj `json:"items"`
+}
+
+// NotificationObj is notification returned by StackExchangeAPI
+type NotificationObj struct {
+	NotificationType string  `json:"notification_type"`
+	Site             SiteObj `json:"site"`
+	CreationDate     int64   `json:"creation_date"`
+	Body             string  `json:"body"`
+	PostID           int     `json:"post_id"`
+	IsUnread         bool    `json:"is_unread"`
+}
+
+// InboxItem returns database record of notification
+func (n *NotificationObj) InboxItem() StackExchangeInboxItem {
+	link := ""
+	if n.PostID > 0 && n.Site.SiteURL != "" {
+		link = fmt.Sprintf("%s/q/%d", n.Site.SiteURL, n.PostID)
+	}
+	return StackExchangeInboxItem{
+		Key: fmt.Sprintf("%s:%s:%s:%d:%d", InboxSourceNotification, n.NotificationType,
+			n.Site.APISiteParameter, n.PostID, n.CreationDate),
+		Source:   InboxSourceNotification,
+		ItemType: n.NotificationType,
+		Link:     link,
+		Body:     n.Body,
+		Site:     n.Site.Name,
+		Created:  time.Unix(n.CreationDate, 0).UTC(),
+	}
+}
+
+func (r *InboxWrapperObj) apiError() error {
+	return apiError(r.ErrorID, r.ErrorName, r.ErrorMessage)
+}
+
+func (r *NotificationsWrapperObj) apiError() error {
+	return apiError(r.ErrorID, r.ErrorName, r.ErrorMessage)
+}
+
+// PageInfo of response
+func (r *InboxWrapperObj) PageInfo() PageInfo {
+	return PageInfo{len(r.Items), r.Page, r.HasMore, r.Backoff, r.QuotaMax, r.QuotaRemaining}
+}
+
+// PageInfo of response
+func (r *NotificationsWrapperObj) PageInfo() PageInfo {
+	return PageInfo{len(r.Items), r.Page, r.HasMore, r.Backoff, r.QuotaMax, r.QuotaRemaining}
+}
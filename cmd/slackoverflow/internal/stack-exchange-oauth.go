@@ -0,0 +1,186 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/howi-ce/howi/std/errors"
+)
+
+// StackExchangeOAuthHost is host of Stack Exchange OAuth dialog
+const StackExchangeOAuthHost = "https://stackoverflow.com"
+
+// oauthImplicitPage passes token from URL fragment, which browser never
+// sends to server, to token endpoint of the redirect listener
+const oauthImplicitPage = `<!DOCTYPE html>
+<html><head><title>SlackOverflow</title></head>
+<body><p id="status">Completing login...</p>
+<script>
+var xhr = new XMLHttpRequest();
+xhr.open("GET", "token?" + window.location.hash.substring(1));
+xhr.onload = function() { document.getElementById("status").textContent = xhr.responseText; };
+xhr.send();
+</script></body></html>`
+
+// StackExchangeOAuth runs OAuth flow https://api.stackexchange.com/docs/authentication
+// Explicit flow is used when ClientSecret is set, implicit flow otherwise.
+type StackExchangeOAuth struct {
+	ClientID     string
+	ClientSecret string
+	Scope        string
+	RedirectURL  string
+	// Host of OAuth dialog, StackExchangeOAuthHost by default
+	Host string
+	// Transport used to exchange code for token, default if nil
+	Transport http.RoundTripper
+	state     string
+}
+
+// StackExchangeToken is access token received from OAuth flow
+type StackExchangeToken struct {
+	AccessToken string
+	// Expires is zero if token was requested with no_expiry scope
+	Expires time.Time
+}
+
+// AuthURL returns URL user must open to authorize SlackOverflow
+func (o *StackExchangeOAuth) AuthURL() (string, error) {
+	if o.ClientID == "" {
+		return "", errors.New("Stack Exchange client id is required for login")
+	}
+	if o.state == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		o.state = hex.EncodeToString(b)
+	}
+	path := "/oauth/dialog"
+	if o.ClientSecret != "" {
+		path = "/oauth"
+	}
+	query := url.Values{}
+	query.Set("client_id", o.ClientID)
+	query.Set("redirect_uri", o.RedirectURL)
+	query.Set("state", o.state)
+	if o.Scope != "" {
+		query.Set("scope", o.Scope)
+	}
+	return o.host() + path + "?" + query.Encode(), nil
+}
+
+// Handler serves redirect of OAuth dialog and sends received token or
+// error to result
+func (o *StackExchangeOAuth) Handler(result chan<- error, token *StackExchangeToken) http.Handler {
+	mux := http.NewServeMux()
+	done := func(w http.ResponseWriter, err error) {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "SlackOverflow is now authorized, you can close this window.")
+		}
+		select {
+		case result <- err:
+		default:
+		}
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if msg := query.Get("error_description"); msg != "" {
+			done(w, errors.Newf("Stack Exchange login failed: %s", msg))
+			return
+		}
+		if o.ClientSecret == "" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, oauthImplicitPage)
+			return
+		}
+		if query.Get("state") != o.state {
+			done(w, errors.New("Stack Exchange login failed: state mismatch"))
+			return
+		}
+		t, err := o.exchange(query.Get("code"))
+		if err == nil {
+			*token = t
+		}
+		done(w, err)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if msg := query.Get("error_description"); msg != "" {
+			done(w, errors.Newf("Stack Exchange login failed: %s", msg))
+			return
+		}
+		if query.Get("state") != o.state {
+			done(w, errors.New("Stack Exchange login failed: state mismatch"))
+			return
+		}
+		t, err := parseToken(query.Get("access_token"), query.Get("expires"))
+		if err == nil {
+			*token = t
+		}
+		done(w, err)
+	})
+	return mux
+}
+
+// exchange code for access token in explicit flow
+func (o *StackExchangeOAuth) exchange(code string) (StackExchangeToken, error) {
+	if code == "" {
+		return StackExchangeToken{}, errors.New("Stack Exchange login failed: no code received")
+	}
+	client := &http.Client{Transport: o.Transport, Timeout: 30 * time.Second}
+	resp, err := client.PostForm(o.host()+"/oauth/access_token/json", url.Values{
+		"client_id":     {o.ClientID},
+		"client_secret": {o.ClientSecret},
+		"code":          {code},
+		"redirect_uri":  {o.RedirectURL},
+	})
+	if err != nil {
+		return StackExchangeToken{}, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return StackExchangeToken{}, err
+	}
+	var result struct {
+		AccessToken string `json:"access_token"`
+		Expires     int    `json:"expires"`
+	}
+	if err = json.Unmarshal(body, &result); err != nil || resp.StatusCode != http.StatusOK {
+		return StackExchangeToken{}, errors.Newf("Stack Exchange login failed: %s", strings.TrimSpace(string(body)))
+	}
+	return parseToken(result.AccessToken, strconv.Itoa(result.Expires))
+}
+
+func (o *StackExchangeOAuth) host() string {
+	if o.Host == "" {
+		return StackExchangeOAuthHost
+	}
+	return strings.TrimRight(o.Host, "/")
+}
+
+// parseToken with expires in seconds, empty or 0 if token does not expire
+func parseToken(accessToken string, expires string) (StackExchangeToken, error) {
+	if accessToken == "" {
+		return StackExchangeToken{}, errors.New("Stack Exchange login failed: no access token received")
+	}
+	token := StackExchangeToken{AccessToken: accessToken}
+	if seconds, err := strconv.Atoi(expires); err == nil && seconds > 0 {
+		token.Expires = time.Now().Add(time.Duration(seconds) * time.Second).UTC()
+	}
+	return token, nil
+}
@@ -0,0 +1,247 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PageInfo is paging, quota and backoff info of API response wrapper
+type PageInfo struct {
+	Items          int
+	Page           int
+	HasMore        bool
+	Backoff        int
+	QuotaMax       int
+	QuotaRemaining int
+}
+
+// PageFetcher fetches given page of list endpoint
+type PageFetcher func(page int) (PageInfo, error)
+
+// Pager iterates pages of Stack Exchange list endpoint until there are no
+// more results or MaxPages, MaxItems or QuotaFloor is reached. Results of
+// current page are available in endpoint Result after Next returns true.
+//
+//	pager := NewPager(searchAdvanced.Pages())
+//	for pager.Next() {
+//		for _, q := range searchAdvanced.Result.Items { ... }
+//	}
+//	if err := pager.Err(); err != nil { ... }
+type Pager struct {
+	// MaxPages to fetch, 0 for no limit
+	MaxPages int
+	// MaxItems stops paging once this many items are received, last page is
+	// not truncated so slightly more items may be received. 0 for no limit.
+	MaxItems int
+	// QuotaFloor stops paging when remaining quota drops to this value
+	QuotaFloor int
+
+	fetch  PageFetcher
+	page   int
+	items  int
+	last   PageInfo
+	done   bool
+	reason string
+	err    error
+}
+
+// NewPager returns pager fetching at most 10 pages
+func NewPager(fetch PageFetcher) *Pager {
+	return &Pager{
+		MaxPages: 10,
+		fetch:    fetch,
+	}
+}
+
+// Next fetches next page, returns false when paging is done or failed
+func (p *Pager) Next() bool {
+	if p.done {
+		return false
+	}
+	if p.page > 0 && !p.canContinue() {
+		p.done = true
+		return false
+	}
+	// Backoff of previous response is respected by StackExchangeClient
+	p.page++
+	info, err := p.fetch(p.page)
+	if err != nil {
+		p.err = err
+		p.reason = "error"
+		p.done = true
+		return false
+	}
+	p.last = info
+	p.items += info.Items
+	return true
+}
+
+// canContinue checks stop conditions after received page
+func (p *Pager) canContinue() bool {
+	switch {
+	case !p.last.HasMore:
+		p.reason = "no more results"
+	case p.MaxPages > 0 && p.page >= p.MaxPages:
+		p.reason = "max pages reached"
+	case p.MaxItems > 0 && p.items >= p.MaxItems:
+		p.reason = "max items reached"
+	case p.last.QuotaRemaining <= p.QuotaFloor:
+		p.reason = "quota floor reached"
+	default:
+		return true
+	}
+	return false
+}
+
+// Err returns error which stopped paging
+func (p *Pager) Err() error {
+	return p.err
+}
+
+// Complete returns true if all results were received
+func (p *Pager) Complete() bool {
+	return p.done && p.err == nil && !p.last.HasMore
+}
+
+// Page returns number of last fetched page
+func (p *Pager) Page() int {
+	return p.page
+}
+
+// Items returns number of received items
+func (p *Pager) Items() int {
+	return p.items
+}
+
+// StopReason describes why paging stopped
+func (p *Pager) StopReason() string {
+	return p.reason
+}
+
+// APIError is error returned by Stack Exchange API
+// https://api.stackexchange.com/docs/error-handling
+type APIError struct {
+	ID      int
+	Name    string
+	Message string
+	// StatusCode of HTTP response, API errors are usually returned with 400
+	StatusCode int
+}
+
+// Error message
+func (e *APIError) Error() string {
+	return fmt.Sprintf("Stack Exchange: %s (%d) %s", e.Name, e.ID, e.Message)
+}
+
+// AccessTokenInvalid returns true if access token is missing, expired,
+// revoked or compromised and user must login again
+func (e *APIError) AccessTokenInvalid() bool {
+	switch e.ID {
+	case 401, 402, 406:
+		return true
+	}
+	return strings.Contains(e.Name, "access_token") || strings.Contains(e.Message, "access_token")
+}
+
+// IsAccessTokenError returns true if err is API error about invalid access token
+func IsAccessTokenError(err error) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.AccessTokenInvalid()
+}
+
+// apiError returns error of API response wrapper if any
+func apiError(id int, name string, message string) error {
+	if id == 0 {
+		return nil
+	}
+	return &APIError{ID: id, Name: name, Message: message}
+}
+
+// pageResult is API response wrapper with paging info
+type pageResult interface {
+	PageInfo() PageInfo
+	apiError() error
+}
+
+// pageInfo returns paging info of response, API errors are returned with
+// HTTP 400 and decoded into response so these take precedence
+func pageInfo(result pageResult, err error) (PageInfo, error) {
+	if reflect.ValueOf(result).IsNil() {
+		return PageInfo{}, err
+	}
+	if apiErr := result.apiError(); apiErr != nil {
+		return result.PageInfo(), apiErr
+	}
+	if err != nil {
+		return PageInfo{}, err
+	}
+	return result.PageInfo(), nil
+}
+
+func (r *QuestionsWrapperObj) apiError() error {
+	return apiError(r.ErrorID, r.ErrorName, r.ErrorMessage)
+}
+
+func (r *AnswersWrapperObj) apiError() error {
+	return apiError(r.ErrorID, r.ErrorName, r.ErrorMessage)
+}
+
+func (r *RevisionsWrapperObj) apiError() error {
+	return apiError(r.ErrorID, r.ErrorName, r.ErrorMessage)
+}
+
+// PageInfo of response
+func (r *QuestionsWrapperObj) PageInfo() PageInfo {
+	return PageInfo{len(r.Items), r.Page, r.HasMore, r.Backoff, r.QuotaMax, r.QuotaRemaining}
+}
+
+// PageInfo of response
+func (r *AnswersWrapperObj) PageInfo() PageInfo {
+	return PageInfo{len(r.Items), r.Page, r.HasMore, r.Backoff, r.QuotaMax, r.QuotaRemaining}
+}
+
+// PageInfo of response
+func (r *RevisionsWrapperObj) PageInfo() PageInfo {
+	return PageInfo{len(r.Items), r.Page, r.HasMore, r.Backoff, r.QuotaMax, r.QuotaRemaining}
+}
+
+// Pages returns fetcher for Pager
+func (sa *SearchAdvanced) Pages() PageFetcher {
+	return func(page int) (PageInfo, error) {
+		sa.SetPage(page)
+		_, err := sa.Get()
+		return pageInfo(sa.Result, err)
+	}
+}
+
+// Pages returns fetcher for Pager
+func (q *Questions) Pages(ids string) PageFetcher {
+	return func(page int) (PageInfo, error) {
+		q.SetPage(page)
+		_, err := q.Get(ids)
+		return pageInfo(q.Result, err)
+	}
+}
+
+// Pages returns fetcher for Pager
+func (a *AnswersOnQuestions) Pages(ids string) PageFetcher {
+	return func(page int) (PageInfo, error) {
+		a.SetPage(page)
+		_, err := a.Get(ids)
+		return pageInfo(a.Result, err)
+	}
+}
+
+// Pages returns fetcher for Pager
+func (r *PostsRevisions) Pages(ids string) PageFetcher {
+	return func(page int) (PageInfo, error) {
+		r.SetPage(page)
+		_, err := r.Get(ids)
+		return pageInfo(r.Result, err)
+	}
+}
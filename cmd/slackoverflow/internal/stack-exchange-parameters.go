@@ -0,0 +1,330 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/howi-ce/howi/std/errors"
+)
+
+// ParameterType of Stack Exchange query parameter
+type ParameterType int
+
+// Parameter types, values are validated and normalized by type
+const (
+	ParamString ParameterType = iota
+	ParamBool
+	ParamInt
+	ParamDate
+	ParamEnum
+	ParamList
+)
+
+// String name of parameter type
+func (t ParameterType) String() string {
+	switch t {
+	case ParamBool:
+		return "bool"
+	case ParamInt:
+		return "int"
+	case ParamDate:
+		return "date"
+	case ParamEnum:
+		return "enum"
+	case ParamList:
+		return "list"
+	}
+	return "string"
+}
+
+// Parameters Stack Exchange query parameters
+type Parameters struct {
+	applied map[string]Parameter
+	allowed map[string]Parameter
+}
+
+// Allow parameter of given type to be set
+func (p *Parameters) Allow(param string, typ ParameterType, value interface{}, desc string) {
+	if p.allowed == nil {
+		p.allowed = make(map[string]Parameter)
+	}
+	p.allowed[param] = Parameter{param: param, typ: typ, value: value, description: desc}
+}
+
+// AllowEnum allows parameter which value must be one of given values
+func (p *Parameters) AllowEnum(param string, value string, desc string, values ...string) {
+	p.Allow(param, ParamEnum, value, desc)
+	allowed := p.allowed[param]
+	allowed.values = values
+	p.allowed[param] = allowed
+}
+
+// IsSet return true is parameter is set
+func (p *Parameters) IsSet(param string) bool {
+	if _, ok := p.applied[param]; ok {
+		return true
+	}
+	return false
+}
+
+// Set adds or moifies a parameter given by the key and value. Unknown
+// parameter, empty value or value invalid for parameter type is error,
+// use Delete to unset parameter.
+func (p *Parameters) Set(param string, value interface{}) error {
+	allowed, ok := p.allowed[param]
+	if !ok {
+		return p.unknown(param)
+	}
+	if value == nil || value == "" {
+		return errors.Newf("empty value for %q, remove the parameter to use the default", param)
+	}
+	normalized, err := allowed.normalize(value)
+	if err != nil {
+		return errors.Newf("invalid value for %q: %s", param, err.Error())
+	}
+	if p.applied == nil {
+		p.applied = make(map[string]Parameter)
+	}
+	allowed.value = normalized
+	p.applied[param] = allowed
+	return nil
+}
+
+// unknown returns error listing allowed parameters
+func (p *Parameters) unknown(param string) error {
+	names := p.AllowedNames()
+	for _, name := range names {
+		if d := editDistance(param, name); d <= 2 && d < len(name)/2 {
+			return errors.Newf("unknown parameter %q, did you mean %q?", param, name)
+		}
+	}
+	return errors.Newf("unknown parameter %q, allowed parameters are: %s", param, strings.Join(names, ", "))
+}
+
+// ValueOf returns value of given parameter if it is set
+func (p *Parameters) ValueOf(param string) string {
+	if val, ok := p.applied[param]; ok {
+		return val.String()
+	}
+	return ""
+}
+
+// Delete the given parameter
+func (p *Parameters) Delete(param string) {
+	delete(p.applied, param)
+}
+
+// IsAllowed return true is parameter is allowed by this endpoint
+func (p *Parameters) IsAllowed(param string) bool {
+	if _, ok := p.allowed[param]; ok {
+		return true
+	}
+	return false
+}
+
+// GetAllowed returns parameters accepted by this endpoint
+func (p *Parameters) GetAllowed() map[string]Parameter {
+	return p.allowed
+}
+
+// AllowedNames returns sorted names of parameters accepted by this endpoint
+func (p *Parameters) AllowedNames() []string {
+	var names []string
+	for name := range p.allowed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyDefaults apply default parameters
+func (p *Parameters) ApplyDefaults() {
+	// Do nothing if no defaults are set
+	if p.allowed == nil {
+		return
+	}
+	for param, value := range p.GetAllowed() {
+		// Set default value if parameter has not been set and it has
+		// default, defaults are declared in code and always valid
+		if !p.IsSet(param) && value.value != nil && value.value != "" {
+			_ = p.Set(param, value.value)
+		}
+	}
+}
+
+// GetApplied returns parameters which have been set
+func (p *Parameters) GetApplied() map[string]Parameter {
+	return p.applied
+}
+
+// Parameter Stack Exchange parameter
+type Parameter struct {
+	param       string
+	typ         ParameterType
+	value       interface{}
+	values      []string
+	description string
+}
+
+// String value of the parameter
+func (p *Parameter) String() string {
+	if p.value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", p.value)
+}
+
+// Decription of this parameter
+func (p *Parameter) Decription() string {
+	return p.description
+}
+
+// Type of this parameter
+func (p *Parameter) Type() ParameterType {
+	return p.typ
+}
+
+// TypeInfo returns type with allowed values e.g. enum(asc|desc)
+func (p *Parameter) TypeInfo() string {
+	switch p.typ {
+	case ParamEnum:
+		return fmt.Sprintf("enum(%s)", strings.Join(p.values, "|"))
+	case ParamDate:
+		return "date(unix|2006-01-02)"
+	case ParamList:
+		return "list(a;b)"
+	}
+	return p.typ.String()
+}
+
+// normalize validates value and returns it as string sent to API
+func (p *Parameter) normalize(value interface{}) (string, error) {
+	switch p.typ {
+	case ParamBool:
+		switch v := value.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return "", errors.Newf("%q is not true or false", v)
+			}
+			return strconv.FormatBool(b), nil
+		}
+	case ParamInt:
+		switch v := value.(type) {
+		case int:
+			return strconv.Itoa(v), nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case string:
+			i, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return "", errors.Newf("%q is not a number", v)
+			}
+			return strconv.Itoa(i), nil
+		}
+	case ParamDate:
+		switch v := value.(type) {
+		case int:
+			return strconv.Itoa(v), nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case time.Time:
+			return strconv.FormatInt(v.Unix(), 10), nil
+		case string:
+			return normalizeDate(strings.TrimSpace(v))
+		}
+	case ParamEnum:
+		s := strings.TrimSpace(fmt.Sprintf("%v", value))
+		for _, allowed := range p.values {
+			if s == allowed {
+				return s, nil
+			}
+		}
+		return "", errors.Newf("%q must be one of %s", s, strings.Join(p.values, ", "))
+	case ParamList:
+		var items []string
+		switch v := value.(type) {
+		case []string:
+			items = v
+		case string:
+			items = strings.Split(v, ";")
+		default:
+			return "", errors.Newf("%v is not a semicolon delimited list", value)
+		}
+		var list []string
+		for _, item := range items {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return strings.Join(list, ";"), nil
+	default:
+		return fmt.Sprintf("%v", value), nil
+	}
+	return "", errors.Newf("unsupported value %v for %s parameter", value, p.typ)
+}
+
+// normalizeDate accepts unix timestamp, date or RFC3339 time
+func normalizeDate(value string) (string, error) {
+	t, err := ParseDate(value)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(t.Unix(), 10), nil
+}
+
+// ParseDate parses "now", unix timestamp, date e.g. 2017-01-02 or RFC3339 time
+func ParseDate(value string) (time.Time, error) {
+	if value == "now" {
+		return time.Now().UTC(), nil
+	}
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0).UTC(), nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, errors.Newf("%q is not unix timestamp or date e.g. 2017-01-02", value)
+}
+
+// editDistance returns Levenshtein distance of given strings
+func editDistance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
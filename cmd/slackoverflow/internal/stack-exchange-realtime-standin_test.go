@@ -0,0 +1,145 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/howi-ce/howi/std/errors"
+	"golang.org/x/net/websocket"
+)
+
+// newStackExchangeRealtimeStandIn starts local stand-in for Stack Exchange
+// real-time WebSocket. Pass URL() to NewStackExchangeRealtime to exercise
+// the real-time source without network access.
+func newStackExchangeRealtimeStandIn() *stackExchangeRealtimeStandIn {
+	s := &stackExchangeRealtimeStandIn{
+		subscriptions: make(map[string]bool),
+		subscribed:    make(chan string, 100),
+		heartbeats:    make(chan string, 10),
+	}
+	s.server = httptest.NewServer(websocket.Handler(s.serve))
+	return s
+}
+
+// stackExchangeRealtimeStandIn is local real-time WebSocket server
+type stackExchangeRealtimeStandIn struct {
+	mu            sync.Mutex
+	server        *httptest.Server
+	conn          *websocket.Conn
+	connections   int
+	subscriptions map[string]bool
+	subscribed    chan string
+	heartbeats    chan string
+}
+
+// URL of the stand-in WebSocket
+func (s *stackExchangeRealtimeStandIn) URL() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http") + "/"
+}
+
+// Connections returns number of WebSocket connections accepted so far
+func (s *stackExchangeRealtimeStandIn) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// WaitSubscribed blocks until client has subscribed to channel or timeout is reached
+func (s *stackExchangeRealtimeStandIn) WaitSubscribed(channel string, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case c := <-s.subscribed:
+			if c == channel {
+				return nil
+			}
+		case <-deadline:
+			return errors.Newf("client did not subscribe to %s", channel)
+		}
+	}
+}
+
+// Publish new question on channel e.g. 1-questions-newest-tag-aframe
+func (s *stackExchangeRealtimeStandIn) Publish(channel string, questionID int, tags ...string) error {
+	data, err := json.Marshal(realtimeQuestion{
+		ID:                  questionID,
+		SiteBaseHostAddress: "stackoverflow.com",
+		Tags:                tags,
+	})
+	if err != nil {
+		return err
+	}
+	return s.send(channel, realtimeMessage{Action: channel, Data: string(data)})
+}
+
+// Heartbeat sends heartbeat and waits for client to answer it
+func (s *stackExchangeRealtimeStandIn) Heartbeat(timeout time.Duration) error {
+	if err := s.send("", realtimeMessage{Action: "hb", Data: "hb"}); err != nil {
+		return err
+	}
+	select {
+	case <-s.heartbeats:
+		return nil
+	case <-time.After(timeout):
+		return errors.New("heartbeat was not answered")
+	}
+}
+
+// Drop closes current connection without notice
+func (s *stackExchangeRealtimeStandIn) Drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// Close the stand-in server
+func (s *stackExchangeRealtimeStandIn) Close() {
+	s.Drop()
+	s.server.Close()
+}
+
+func (s *stackExchangeRealtimeStandIn) send(channel string, msg realtimeMessage) error {
+	s.mu.Lock()
+	conn := s.conn
+	subscribed := channel == "" || s.subscriptions[channel]
+	s.mu.Unlock()
+	if conn == nil {
+		return errors.New("no real-time client connected")
+	}
+	if !subscribed {
+		return errors.Newf("client is not subscribed to %s", channel)
+	}
+	return websocket.JSON.Send(conn, msg)
+}
+
+func (s *stackExchangeRealtimeStandIn) serve(conn *websocket.Conn) {
+	s.mu.Lock()
+	s.conn = conn
+	s.connections++
+	s.subscriptions = make(map[string]bool)
+	s.mu.Unlock()
+	for {
+		var msg string
+		if err := websocket.Message.Receive(conn, &msg); err != nil {
+			return
+		}
+		if msg == "hb" {
+			s.heartbeats <- msg
+			continue
+		}
+		s.mu.Lock()
+		s.subscriptions[msg] = true
+		s.mu.Unlock()
+		s.subscribed <- msg
+	}
+}
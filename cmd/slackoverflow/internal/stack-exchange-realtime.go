@@ -0,0 +1,224 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/howi-ce/howi/addon/application/plugin/cli"
+	"github.com/howi-ce/howi/std/errors"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// StackExchangeRealtimeURL is WebSocket publishing Stack Exchange site events
+	StackExchangeRealtimeURL = "wss://qa.sockets.stackexchange.com/"
+	realtimeOrigin           = "https://stackexchange.com"
+	// realtimeIdleTimeout reconnects when not even heartbeat is received
+	realtimeIdleTimeout = 5 * time.Minute
+)
+
+// NewStackExchangeRealtime returns real-time source of new questions on
+// given tags of site, siteID is numeric id of site (Stack Overflow is 1)
+func NewStackExchangeRealtime(wsURL string, siteID int, tags []string) *StackExchangeRealtime {
+	if wsURL == "" {
+		wsURL = StackExchangeRealtimeURL
+	}
+	return &StackExchangeRealtime{
+		wsURL:       wsURL,
+		siteID:      siteID,
+		tags:        tags,
+		MinBackoff:  socketModeMinBackoff,
+		MaxBackoff:  socketModeMaxBackoff,
+		IdleTimeout: realtimeIdleTimeout,
+		queue:       make(map[int]bool),
+		queued:      make(chan struct{}, 1),
+	}
+}
+
+// StackExchangeRealtime subscribes to {siteId}-questions-newest-tag-{tag}
+// channels and queues ids of new questions, questions themselves must be
+// fetched with questions/{ids}.
+type StackExchangeRealtime struct {
+	wsURL  string
+	siteID int
+	tags   []string
+	// MinBackoff is the delay before first reconnect attempt
+	MinBackoff time.Duration
+	// MaxBackoff caps the delay between reconnect attempts
+	MaxBackoff time.Duration
+	// IdleTimeout reconnects if nothing is received for this long
+	IdleTimeout time.Duration
+
+	mu        sync.Mutex
+	connected bool
+	queue     map[int]bool
+	queued    chan struct{}
+}
+
+// realtimeMessage is message received from Stack Exchange WebSocket, Data
+// is JSON encoded string
+type realtimeMessage struct {
+	Action string `json:"action"`
+	Data   string `json:"data"`
+}
+
+// realtimeQuestion is data of questions-newest-tag message
+type realtimeQuestion struct {
+	ID                  int      `json:"id"`
+	SiteBaseHostAddress string   `json:"siteBaseHostAddress"`
+	Tags                []string `json:"tags"`
+}
+
+// Channels subscribed by real-time source
+func (r *StackExchangeRealtime) Channels() []string {
+	channels := make([]string, len(r.tags))
+	for i, tag := range r.tags {
+		channels[i] = fmt.Sprintf("%d-questions-newest-tag-%s", r.siteID, strings.ToLower(tag))
+	}
+	return channels
+}
+
+// Connected returns true while subscribed to WebSocket, polling for new
+// questions is not needed meanwhile. Returns false for nil source.
+func (r *StackExchangeRealtime) Connected() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.connected
+}
+
+// Queued is signaled when new question ids are queued
+func (r *StackExchangeRealtime) Queued() <-chan struct{} {
+	return r.queued
+}
+
+// TakeIDs returns queued question ids and empties the queue
+func (r *StackExchangeRealtime) TakeIDs() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids := make([]int, 0, len(r.queue))
+	for id := range r.queue {
+		ids = append(ids, id)
+	}
+	r.queue = make(map[int]bool)
+	sort.Ints(ids)
+	return ids
+}
+
+// Run real-time source until context is canceled, reconnecting with
+// exponential backoff when connection is lost.
+func (r *StackExchangeRealtime) Run(ctx context.Context, w *cli.Worker) error {
+	if len(r.tags) == 0 {
+		return errors.New("Stack Exchange real-time: no tags to subscribe")
+	}
+	backoff := r.MinBackoff
+	for {
+		connected, err := r.connect(ctx, w)
+		r.setConnected(false)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			backoff = r.MinBackoff
+		}
+		if err != nil {
+			w.Log.Warningf("Stack Exchange real-time: %s, polling until reconnected in %s", err.Error(), backoff)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(jitter(backoff)):
+		}
+		if !connected {
+			backoff *= 2
+			if backoff > r.MaxBackoff {
+				backoff = r.MaxBackoff
+			}
+		}
+	}
+}
+
+// connect subscribes to channels and serves connection until it is closed.
+// Returns true if subscriptions were sent.
+func (r *StackExchangeRealtime) connect(ctx context.Context, w *cli.Worker) (bool, error) {
+	conn, err := websocket.Dial(r.wsURL, "", realtimeOrigin)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	channels := r.Channels()
+	subscribed := make(map[string]bool)
+	for _, channel := range channels {
+		if err := websocket.Message.Send(conn, channel); err != nil {
+			return false, err
+		}
+		subscribed[channel] = true
+	}
+	r.setConnected(true)
+	w.Log.Okf("Stack Exchange real-time: subscribed to %s", strings.Join(channels, ", "))
+
+	for {
+		if r.IdleTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(r.IdleTimeout))
+		}
+		msg := realtimeMessage{}
+		if err := websocket.JSON.Receive(conn, &msg); err != nil {
+			return true, err
+		}
+		switch {
+		case msg.Action == "hb":
+			// Heartbeat must be answered or server closes the connection
+			if err := websocket.Message.Send(conn, msg.Data); err != nil {
+				return true, err
+			}
+		case subscribed[msg.Action]:
+			q := realtimeQuestion{}
+			if err := json.Unmarshal([]byte(msg.Data), &q); err != nil || q.ID == 0 {
+				w.Log.Debugf("Stack Exchange real-time: invalid message on %s: %s", msg.Action, msg.Data)
+				continue
+			}
+			w.Log.Debugf("Stack Exchange real-time: new question %d on %s", q.ID, msg.Action)
+			r.enqueue(q.ID)
+		default:
+			w.Log.Debugf("Stack Exchange real-time: ignoring message on %s", msg.Action)
+		}
+	}
+}
+
+func (r *StackExchangeRealtime) setConnected(connected bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.connected = connected
+}
+
+func (r *StackExchangeRealtime) enqueue(id int) {
+	r.mu.Lock()
+	r.queue[id] = true
+	r.mu.Unlock()
+	select {
+	case r.queued <- struct{}{}:
+	default:
+	}
+}
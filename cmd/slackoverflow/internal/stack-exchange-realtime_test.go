@@ -0,0 +1,136 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const realtimeChannel = "1-questions-newest-tag-aframe"
+
+// startRealtime runs real-time source of aframe tag against new stand-in
+func startRealtime(t *testing.T, minBackoff time.Duration) (*StackExchangeRealtime, *stackExchangeRealtimeStandIn, context.CancelFunc) {
+	standIn := newStackExchangeRealtimeStandIn()
+	realtime := NewStackExchangeRealtime(standIn.URL(), 1, []string{"AFrame"})
+	realtime.MinBackoff = minBackoff
+	realtime.MaxBackoff = 4 * minBackoff
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		realtime.Run(ctx, newTestWorker())
+		close(done)
+	}()
+	if err := standIn.WaitSubscribed(realtimeChannel, standInTimeout); err != nil {
+		cancel()
+		standIn.Close()
+		t.Fatal(err)
+	}
+	return realtime, standIn, func() {
+		cancel()
+		standIn.Close()
+		<-done
+	}
+}
+
+// waitFor polls cond until it is true or timeout is reached
+func waitFor(cond func() bool, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestStackExchangeRealtimeQueuesQuestions(t *testing.T) {
+	realtime, standIn, stop := startRealtime(t, 10*time.Millisecond)
+	defer stop()
+
+	if !waitFor(realtime.Connected, standInTimeout) {
+		t.Fatal("real-time source is not connected")
+	}
+	if err := standIn.Heartbeat(standInTimeout); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int{102, 101, 102} {
+		if err := standIn.Publish(realtimeChannel, id, "aframe"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case <-realtime.Queued():
+	case <-time.After(standInTimeout):
+		t.Fatal("questions were not queued")
+	}
+	var ids []int
+	waitFor(func() bool {
+		ids = append(ids, realtime.TakeIDs()...)
+		return len(ids) >= 2
+	}, standInTimeout)
+	if !reflect.DeepEqual(ids, []int{101, 102}) {
+		t.Errorf("expected queued ids [101 102], got %v", ids)
+	}
+	if ids := realtime.TakeIDs(); len(ids) != 0 {
+		t.Errorf("expected empty queue, got %v", ids)
+	}
+}
+
+func TestStackExchangeRealtimeFallsBackToPolling(t *testing.T) {
+	var nilSource *StackExchangeRealtime
+	if nilSource.Connected() {
+		t.Error("nil real-time source must not be connected")
+	}
+
+	// Long enough backoff to observe disconnected state
+	realtime, standIn, stop := startRealtime(t, 300*time.Millisecond)
+	defer stop()
+	if !waitFor(realtime.Connected, standInTimeout) {
+		t.Fatal("real-time source is not connected")
+	}
+
+	standIn.Drop()
+	if !waitFor(func() bool { return !realtime.Connected() }, standInTimeout) {
+		t.Fatal("real-time source must report disconnect so new questions are polled")
+	}
+
+	if err := standIn.WaitSubscribed(realtimeChannel, standInTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if !waitFor(realtime.Connected, standInTimeout) {
+		t.Fatal("real-time source did not reconnect")
+	}
+	if n := standIn.Connections(); n != 2 {
+		t.Errorf("expected 2 connections, got %d", n)
+	}
+	if err := standIn.Publish(realtimeChannel, 103, "aframe"); err != nil {
+		t.Fatal(err)
+	}
+	if !waitFor(func() bool { return len(realtime.TakeIDs()) == 1 }, standInTimeout) {
+		t.Error("question published after reconnect was not queued")
+	}
+}
+
+func TestStackExchangeRealtimeUnreachable(t *testing.T) {
+	standIn := newStackExchangeRealtimeStandIn()
+	wsURL := standIn.URL()
+	standIn.Close()
+
+	realtime := NewStackExchangeRealtime(wsURL, 1, []string{"aframe"})
+	realtime.MinBackoff = 10 * time.Millisecond
+	realtime.MaxBackoff = 20 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := realtime.Run(ctx, newTestWorker()); err != nil {
+		t.Fatal(err)
+	}
+	if realtime.Connected() {
+		t.Error("unreachable real-time source must not be connected")
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// TagsInfo https://api.stackexchange.com/docs/tags-by-name
+func (s *StackExchangeClient) TagsInfo() *TagsInfo {
+	info := &TagsInfo{}
+	info.Client = s
+	info.Init()
+	return info
+}
+
+// TagsSynonyms https://api.stackexchange.com/docs/synonyms-by-tags
+func (s *StackExchangeClient) TagsSynonyms() *TagsSynonyms {
+	synonyms := &TagsSynonyms{}
+	synonyms.Client = s
+	synonyms.Init()
+	return synonyms
+}
+
+// TagsRelated https://api.stackexchange.com/docs/related-tags
+func (s *StackExchangeClient) TagsRelated() *TagsRelated {
+	related := &TagsRelated{}
+	related.Client = s
+	related.Init()
+	return related
+}
+
+// TagsInfo - https://api.stackexchange.com/docs/tags-by-name
+type TagsInfo struct {
+	Client     *StackExchangeClient
+	Parameters Parameters
+	Paging
+	Result *TagsWrapperObj
+}
+
+// Init initializes Tags Info module
+func (t *TagsInfo) Init() {
+	t.Parameters.Allow("site", ParamString, "stackoverflow",
+		"site where to get tags from")
+	t.Parameters.AllowEnum("sort", "popular",
+		"sort tags by", "popular", "activity", "name")
+	t.Parameters.AllowEnum("order", "desc",
+		"order of tags", "desc", "asc")
+	t.Parameters.Allow("filter", ParamString, "default",
+		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
+	t.Parameters.Allow("pagesize", ParamInt, 100,
+		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
+	t.Parameters.Allow("page", ParamInt, 1,
+		"Current page to be fetched")
+	t.Parameters.Allow("key", ParamString, t.Client.apiKey,
+		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	t.Parameters.Allow("access_token", ParamString, t.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
+}
+
+// Get request, tags are semicolon delimited
+func (t *TagsInfo) Get(tags string) (bool, error) {
+	endpoint, err := t.Client.GetEndpont("tags/" + escapeTags(tags) + "/info")
+	if err != nil {
+		return false, err
+	}
+	t.Parameters.ApplyDefaults()
+	query := endpoint.Query()
+	for param, value := range t.Parameters.GetApplied() {
+		query.Set(param, value.String())
+	}
+	query.Set("page", fmt.Sprintf("%d", t.GetCurrentPageNr()))
+	endpoint.RawQuery = query.Encode()
+
+	t.Client.WaitBackoff()
+	err = t.Client.httpGet(endpoint.String(), &t.Result)
+	if err != nil {
+		return false, err
+	}
+
+	t.Paging.curentPage = t.Result.Page
+	t.Paging.hasMore = t.Result.HasMore
+	t.Client.SetQuotaMax(t.Result.QuotaMax)
+	t.Client.SetQuotaRemaining(t.Result.QuotaRemaining)
+	t.Client.SetBackoff(t.Result.Backoff)
+
+	return true, err
+}
+
+// Pages returns fetcher for Pager
+func (t *TagsInfo) Pages(tags string) PageFetcher {
+	return func(page int) (PageInfo, error) {
+		t.SetPage(page)
+		_, err := t.Get(tags)
+		return pageInfo(t.Result, err)
+	}
+}
+
+// TagsSynonyms - https://api.stackexchange.com/docs/synonyms-by-tags
+type TagsSynonyms struct {
+	Client     *StackExchangeClient
+	Parameters Parameters
+	Paging
+	Result *TagSynonymsWrapperObj
+}
+
+// Init initializes Tags Synonyms module
+func (t *TagsSynonyms) Init() {
+	t.Parameters.Allow("site", ParamString, "stackoverflow",
+		"site where to get tag synonyms from")
+	t.Parameters.AllowEnum("sort", "applied",
+		"sort synonyms by", "applied", "activity", "creation")
+	t.Parameters.AllowEnum("order", "desc",
+		"order of synonyms", "desc", "asc")
+	t.Parameters.Allow("filter", ParamString, "default",
+		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
+	t.Parameters.Allow("pagesize", ParamInt, 100,
+		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
+	t.Parameters.Allow("page", ParamInt, 1,
+		"Current page to be fetched")
+	t.Parameters.Allow("key", ParamString, t.Client.apiKey,
+		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	t.Parameters.Allow("access_token", ParamString, t.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
+}
+
+// Get request, returns synonyms pointing to semicolon delimited tags or
+// all synonyms of the site if tags is empty
+// https://api.stackexchange.com/docs/tag-synonyms
+func (t *TagsSynonyms) Get(tags string) (bool, error) {
+	method := "tags/synonyms"
+	if tags != "" {
+		method = "tags/" + escapeTags(tags) + "/synonyms"
+	}
+	endpoint, err := t.Client.GetEndpont(method)
+	if err != nil {
+		return false, err
+	}
+	t.Parameters.ApplyDefaults()
+	query := endpoint.Query()
+	for param, value := range t.Parameters.GetApplied() {
+		query.Set(param, value.String())
+	}
+	query.Set("page", fmt.Sprintf("%d", t.GetCurrentPageNr()))
+	endpoint.RawQuery = query.Encode()
+
+	t.Client.WaitBackoff()
+	err = t.Client.httpGet(endpoint.String(), &t.Result)
+	if err != nil {
+		return false, err
+	}
+
+	t.Paging.curentPage = t.Result.Page
+	t.Paging.hasMore = t.Result.HasMore
+	t.Client.SetQuotaMax(t.Result.QuotaMax)
+	t.Client.SetQuotaRemaining(t.Result.QuotaRemaining)
+	t.Client.SetBackoff(t.Result.Backoff)
+
+	return true, err
+}
+
+// Pages returns fetcher for Pager
+func (t *TagsSynonyms) Pages(tags string) PageFetcher {
+	return func(page int) (PageInfo, error) {
+		t.SetPage(page)
+		_, err := t.Get(tags)
+		return pageInfo(t.Result, err)
+	}
+}
+
+// TagsRelated - https://api.stackexchange.com/docs/related-tags
+type TagsRelated struct {
+	Client     *StackExchangeClient
+	Parameters Parameters
+	Paging
+	Result *TagsWrapperObj
+}
+
+// Init initializes Tags Related module
+func (t *TagsRelated) Init() {
+	t.Parameters.Allow("site", ParamString, "stackoverflow",
+		"site where to get related tags from")
+	t.Parameters.Allow("filter", ParamString, "default",
+		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
+	t.Parameters.Allow("pagesize", ParamInt, 30,
+		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
+	t.Parameters.Allow("page", ParamInt, 1,
+		"Current page to be fetched")
+	t.Parameters.Allow("key", ParamString, t.Client.apiKey,
+		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	t.Parameters.Allow("access_token", ParamString, t.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
+}
+
+// Get request, returns tags related to all given semicolon delimited tags
+// (at most 4)
+func (t *TagsRelated) Get(tags string) (bool, error) {
+	endpoint, err := t.Client.GetEndpont("tags/" + escapeTags(tags) + "/related")
+	if err != nil {
+		return false, err
+	}
+	t.Parameters.ApplyDefaults()
+	query := endpoint.Query()
+	for param, value := range t.Parameters.GetApplied() {
+		query.Set(param, value.String())
+	}
+	query.Set("page", fmt.Sprintf("%d", t.GetCurrentPageNr()))
+	endpoint.RawQuery = query.Encode()
+
+	t.Client.WaitBackoff()
+	err = t.Client.httpGet(endpoint.String(), &t.Result)
+	if err != nil {
+		return false, err
+	}
+
+	t.Paging.curentPage = t.Result.Page
+	t.Paging.hasMore = t.Result.HasMore
+	t.Client.SetQuotaMax(t.Result.QuotaMax)
+	t.Client.SetQuotaRemaining(t.Result.QuotaRemaining)
+	t.Client.SetBackoff(t.Result.Backoff)
+
+	return true, err
+}
+
+// Pages returns fetcher for Pager
+func (t *TagsRelated) Pages(tags string) PageFetcher {
+	return func(page int) (PageInfo, error) {
+		t.SetPage(page)
+		_, err := t.Get(tags)
+		return pageInfo(t.Result, err)
+	}
+}
+
+// escapeTags escapes semicolon delimited tags for use in URL path
+func escapeTags(tags string) string {
+	var escaped []string
+	for _, tag := range strings.Split(tags, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			escaped = append(escaped, url.PathEscape(tag))
+		}
+	}
+	return strings.Join(escaped, ";")
+}
+
+// TagsWrapperObj is a API response type
+type TagsWrapperObj struct {
+	Backoff        int      `json:"backoff"`
+	ErrorID        int      `json:"error_id"`
+	ErrorName      string   `json:"error_name"`
+	ErrorMessage   string   `json:"error_message"`
+	HasMore        bool     `json:"has_more"`
+	Page           int      `json:"page"`
+	QuotaMax       int      `json:"quota_max"`
+	QuotaRemaining int      `json:"quota_remaining"`
+	Items          []TagObj `json:"items"`
+}
+
+// TagObj is tag returned by StackExchange API
+type TagObj struct {
+	Name             string `json:"name"`
+	Count            int    `json:"count"`
+	HasSynonyms      bool   `json:"has_synonyms"`
+	IsModeratorOnly  bool   `json:"is_moderator_only"`
+	IsRequired       bool   `json:"is_required"`
+	LastActivityDate int64  `json:"last_activity_date"`
+}
+
+// TagSynonymsWrapperObj is a API response type
+type TagSynonymsWrapperObj struct {
+	Backoff        int             `json:"backoff"`
+	ErrorID        int             `json:"error_id"`
+	ErrorName      string          `json:"error_name"`
+	ErrorMessage   string          `json:"error_message"`
+	HasMore        bool            `json:"has_more"`
+	Page           int             `json:"page"`
+	QuotaMax       int             `json:"quota_max"`
+	QuotaRemaining int             `json:"quota_remaining"`
+	Items          []TagSynonymObj `json:"items"`
+}
+
+// TagSynonymObj is tag synonym returned by StackExchange API, questions
+// tagged FromTag are retagged to ToTag
+type TagSynonymObj struct {
+	FromTag         string `json:"from_tag"`
+	ToTag           string `json:"to_tag"`
+	AppliedCount    int    `json:"applied_count"`
+	CreationDate    int64  `json:"creation_date"`
+	LastAppliedDate int64  `json:"last_applied_date"`
+}
+
+func (r *TagsWrapperObj) apiError() error {
+	return apiError(r.ErrorID, r.ErrorName, r.ErrorMessage)
+}
+
+func (r *TagSynonymsWrapperObj) apiError() error {
+	return apiError(r.ErrorID, r.ErrorName, r.ErrorMessage)
+}
+
+// PageInfo of response
+func (r *TagsWrapperObj) PageInfo() PageInfo {
+	return PageInfo{len(r.Items), r.Page, r.HasMore, r.Backoff, r.QuotaMax, r.QuotaRemaining}
+}
+
+// PageInfo of response
+func (r *TagSynonymsWrapperObj) PageInfo() PageInfo {
+	return PageInfo{len(r.Items), r.Page, r.HasMore, r.Backoff, r.QuotaMax, r.QuotaRemaining}
+}
@@ -0,0 +1,249 @@
+// Copyright © 2016 -2017 A-Frame authors.
+// Use of this source code is governed by a MIT License
+// that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/howi-ce/howi/std/errors"
+)
+
+const (
+	// TopAnswerersMonth period of top answerers
+	TopAnswerersMonth = "month"
+	// TopAnswerersAllTime period of top answerers
+	TopAnswerersAllTime = "all_time"
+)
+
+// Users https://api.stackexchange.com/docs/users-by-ids
+func (s *StackExchangeClient) Users() *Users {
+	users := &Users{}
+	users.Client = s
+	users.Init()
+	return users
+}
+
+// TagsTopAnswerers https://api.stackexchange.com/docs/top-answerers-on-tags
+func (s *StackExchangeClient) TagsTopAnswerers() *TagsTopAnswerers {
+	topAnswerers := &TagsTopAnswerers{}
+	topAnswerers.Client = s
+	topAnswerers.Init()
+	return topAnswerers
+}
+
+// Users - https://api.stackexchange.com/docs/users-by-ids
+type Users struct {
+	Client     *StackExchangeClient
+	Parameters Parameters
+	Paging
+	Result *UsersWrapperObj
+}
+
+// Init initializes Users module
+func (u *Users) Init() {
+	u.Parameters.Allow("site", ParamString, "stackoverflow",
+		"site where to get users from")
+	u.Parameters.AllowEnum("sort", "reputation",
+		"sort users by", "reputation", "creation", "name", "modified")
+	u.Parameters.AllowEnum("order", "desc",
+		"order of users", "desc", "asc")
+	u.Parameters.Allow("filter", ParamString, "default",
+		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
+	u.Parameters.Allow("pagesize", ParamInt, 100,
+		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
+	u.Parameters.Allow("page", ParamInt, 1,
+		"Current page to be fetched")
+	u.Parameters.Allow("key", ParamString, u.Client.apiKey,
+		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	u.Parameters.Allow("access_token", ParamString, u.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
+}
+
+// Get request
+func (u *Users) Get(ids string) (bool, error) {
+	endpoint, err := u.Client.GetEndpont("users/" + ids)
+	if err != nil {
+		return false, err
+	}
+	u.Parameters.ApplyDefaults()
+	query := endpoint.Query()
+	for param, value := range u.Parameters.GetApplied() {
+		query.Set(param, value.String())
+	}
+	query.Set("page", fmt.Sprintf("%d", u.GetCurrentPageNr()))
+	endpoint.RawQuery = query.Encode()
+
+	u.Client.WaitBackoff()
+	err = u.Client.httpGet(endpoint.String(), &u.Result)
+	if err != nil {
+		return false, err
+	}
+
+	u.Paging.curentPage = u.Result.Page
+	u.Paging.hasMore = u.Result.HasMore
+	u.Client.SetQuotaMax(u.Result.QuotaMax)
+	u.Client.SetQuotaRemaining(u.Result.QuotaRemaining)
+	u.Client.SetBackoff(u.Result.Backoff)
+
+	return true, err
+}
+
+// Pages returns fetcher for Pager
+func (u *Users) Pages(ids string) PageFetcher {
+	return func(page int) (PageInfo, error) {
+		u.SetPage(page)
+		_, err := u.Get(ids)
+		return pageInfo(u.Result, err)
+	}
+}
+
+// TagsTopAnswerers - https://api.stackexchange.com/docs/top-answerers-on-tags
+type TagsTopAnswerers struct {
+	Client     *StackExchangeClient
+	Parameters Parameters
+	Paging
+	Result *TagScoresWrapperObj
+}
+
+// Init initializes Tags Top Answerers module
+func (t *TagsTopAnswerers) Init() {
+	t.Parameters.Allow("site", ParamString, "stackoverflow",
+		"site where to get top answerers from")
+	t.Parameters.Allow("filter", ParamString, "default",
+		"Defined Custom Filters https://api.stackexchange.com/docs/filters")
+	t.Parameters.Allow("pagesize", ParamInt, 30,
+		"API. page starts at and defaults to 1, pagesize can be any value between 0 and 100")
+	t.Parameters.Allow("page", ParamInt, 1,
+		"Current page to be fetched")
+	t.Parameters.Allow("key", ParamString, t.Client.apiKey,
+		"Pass this as key when making requests against the Stack Exchange API to receive a higher request quota.")
+	t.Parameters.Allow("access_token", ParamString, t.Client.getAccessToken(),
+		"OAuth access token of the user, see slackoverflow stackexchange login")
+}
+
+// Get request, period is TopAnswerersMonth or TopAnswerersAllTime
+func (t *TagsTopAnswerers) Get(tag string, period string) (bool, error) {
+	if period != TopAnswerersMonth && period != TopAnswerersAllTime {
+		return false, errors.Newf("invalid period %q, must be %s or %s", period, TopAnswerersMonth, TopAnswerersAllTime)
+	}
+	endpoint, err := t.Client.GetEndpont("tags/" + url.PathEscape(tag) + "/top-answerers/" + period)
+	if err != nil {
+		return false, err
+	}
+	t.Parameters.ApplyDefaults()
+	query := endpoint.Query()
+	for param, value := range t.Parameters.GetApplied() {
+		query.Set(param, value.String())
+	}
+	query.Set("page", fmt.Sprintf("%d", t.GetCurrentPageNr()))
+	endpoint.RawQuery = query.Encode()
+
+	t.Client.WaitBackoff()
+	err = t.Client.httpGet(endpoint.String(), &t.Result)
+	if err != nil {
+		return false, err
+	}
+
+	t.Paging.curentPage = t.Result.Page
+	t.Paging.hasMore = t.Result.HasMore
+	t.Client.SetQuotaMax(t.Result.QuotaMax)
+	t.Client.SetQuotaRemaining(t.Result.QuotaRemaining)
+	t.Client.SetBackoff(t.Result.Backoff)
+
+	return true, err
+}
+
+// Pages returns fetcher for Pager
+func (t *TagsTopAnswerers) Pages(tag string, period string) PageFetcher {
+	return func(page int) (PageInfo, error) {
+		t.SetPage(page)
+		_, err := t.Get(tag, period)
+		return pageInfo(t.Result, err)
+	}
+}
+
+// UsersWrapperObj is a API response type
+type UsersWrapperObj struct {
+	Backoff        int       `json:"backoff"`
+	ErrorID        int       `json:"error_id"`
+	ErrorName      string    `json:"error_name"`
+	ErrorMessage   string    `json:"error_message"`
+	HasMore        bool      `json:"has_more"`
+	Page           int       `json:"page"`
+	QuotaMax       int       `json:"quota_max"`
+	QuotaRemaining int       `json:"quota_remaining"`
+	Items          []UserObj `json:"items"`
+}
+
+// UserObj is user returned by StackExchange API
+type UserObj struct {
+	UID                     int            `json:"user_id"`
+	DisplayName             string         `json:"display_name"`
+	ProfileImage            string         `json:"profile_image"`
+	Link                    string         `json:"link"`
+	Reputation              int            `json:"reputation"`
+	AcceptRate              int            `json:"accept_rate"`
+	BadgeCounts             BadgeCountsObj `json:"badge_counts"`
+	Location                string         `json:"location"`
+	WebsiteURL              string         `json:"website_url"`
+	CreationDate            int64          `json:"creation_date"`
+	LastAccessDate          int64          `json:"last_access_date"`
+	ReputationChangeWeek    int            `json:"reputation_change_week"`
+	ReputationChangeMonth   int            `json:"reputation_change_month"`
+	ReputationChangeQuarter int            `json:"reputation_change_quarter"`
+	ReputationChangeYear    int            `json:"reputation_change_year"`
+}
+
+// ShallowUser returns shallow user stored in StackExchangeUser table
+func (u *UserObj) ShallowUser() ShallowUserObj {
+	return ShallowUserObj{
+		UID:          u.UID,
+		Reputation:   u.Reputation,
+		ProfileImage: u.ProfileImage,
+		DisplayName:  u.DisplayName,
+		Link:         u.Link,
+		AcceptRate:   u.AcceptRate,
+		BadgeCounts:  u.BadgeCounts,
+	}
+}
+
+// TagScoresWrapperObj is a API response type
+type TagScoresWrapperObj struct {
+	Backoff        int           `json:"backoff"`
+	ErrorID        int           `json:"error_id"`
+	ErrorName      string        `json:"error_name"`
+	ErrorMessage   string        `json:"error_message"`
+	HasMore        bool          `json:"has_more"`
+	Page           int           `json:"page"`
+	QuotaMax       int           `json:"quota_max"`
+	QuotaRemaining int           `json:"quota_remaining"`
+	Items          []TagScoreObj `json:"items"`
+}
+
+// TagScoreObj is score of user on tag returned by StackExchange API
+type TagScoreObj struct {
+	User      ShallowUserObj `json:"user"`
+	PostCount int            `json:"post_count"`
+	Score     int            `json:"score"`
+}
+
+func (r *UsersWrapperObj) apiError() error {
+	return apiError(r.ErrorID, r.ErrorName, r.ErrorMessage)
+}
+
+func (r *TagScoresWrapperObj) apiError() error {
+	return apiError(r.ErrorID, r.ErrorName, r.ErrorMessage)
+}
+
+// PageInfo of response
+func (r *UsersWrapperObj) PageInfo() PageInfo {
+	return PageInfo{len(r.Items), r.Page, r.HasMore, r.Backoff, r.QuotaMax, r.QuotaRemaining}
+}
+
+// PageInfo of response
+func (r *TagScoresWrapperObj) PageInfo() PageInfo {
+	return PageInfo{len(r.Items), r.Page, r.HasMore, r.Backoff, r.QuotaMax, r.QuotaRemaining}
+}
@@ -51,9 +51,23 @@ func main() {
 	cnfFlag.SetUsage("set alternative path  to configuration directory. defaults ~/.slackoverflow")
 	appcli.AddFlag(cnfFlag)
 
+	recordFlag := flags.NewStringFlag("record")
+	recordFlag.SetUsage("record Stack Exchange and Slack HTTP requests to given directory, key and tokens are redacted")
+	appcli.AddFlag(recordFlag)
+
+	replayFlag := flags.NewStringFlag("replay")
+	replayFlag.SetUsage("serve HTTP requests from recordings in given directory instead of calling APIs")
+	appcli.AddFlag(replayFlag)
+
+	dryRunFlag := flags.NewBoolFlag("dry-run")
+	dryRunFlag.SetUsage("read and evaluate rules as usual, but only print what would be posted to Slack or stored to database")
+	appcli.AddFlag(dryRunFlag)
+
 	// Attach Commands
 	appcli.AddCommand(commands.Config(so))
+	appcli.AddCommand(commands.Dev(so))
 	appcli.AddCommand(commands.Reconfigure(so))
+	appcli.AddCommand(commands.Report(so))
 	appcli.AddCommand(commands.Run(so))
 	appcli.AddCommand(commands.Service(so))
 	appcli.AddCommand(commands.Slack(so))